@@ -0,0 +1,54 @@
+// Command compare-a11y-runs diffs two accessibility runs saved by the
+// scanner (test-results/a11y-run.json) and exits non-zero when the head run
+// has violations the base run did not. The base run is limited to the page
+// templates the head run scanned, so a partial head run reports no fixes on
+// pages it never visited.
+//
+//	go run ./cmd/compare-a11y-runs base/a11y-run.json head/a11y-run.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"pwarnock-tests/support"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: compare-a11y-runs <base.json> <head.json>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	regressed, err := run(flag.Arg(0), flag.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if regressed {
+		os.Exit(1)
+	}
+}
+
+// run prints the diff and reports whether head has new violations
+func run(basePath, headPath string) (bool, error) {
+	base, err := support.LoadA11yRun(basePath)
+	if err != nil {
+		return false, err
+	}
+	head, err := support.LoadA11yRun(headPath)
+	if err != nil {
+		return false, err
+	}
+
+	diff := support.CompareA11yRuns(base, head)
+	fmt.Println(diff)
+	return len(diff.New) > 0, nil
+}
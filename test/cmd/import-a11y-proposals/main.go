@@ -0,0 +1,76 @@
+// Command import-a11y-proposals files the accessibility issues proposed by a
+// CI run (test-results/a11y-proposals.jsonl) into bd, applying the same
+// fingerprint dedup as local scans.
+//
+//	go run ./cmd/import-a11y-proposals [-dry-run] [-jsonl ../.beads/issues.jsonl] a11y-proposals.jsonl
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"pwarnock-tests/support"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "print what would change without touching the tracker")
+	jsonlPath := flag.String("jsonl", "", "edit this issues.jsonl directly instead of using the bd CLI")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: import-a11y-proposals [flags] <proposals.jsonl>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), *jsonlPath, *dryRun); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run imports every proposal, or plans them against a snapshot when dryRun
+func run(proposalsPath, jsonlPath string, dryRun bool) error {
+	proposals, err := support.LoadProposals(proposalsPath)
+	if err != nil {
+		return err
+	}
+
+	var tracker support.IssueTracker = support.NewBdTracker()
+	if jsonlPath != "" {
+		tracker = support.NewJSONLTracker(jsonlPath)
+	}
+
+	if dryRun {
+		issues, err := tracker.List(support.IssueFilter{})
+		if err != nil {
+			return err
+		}
+		// Plan against an in-memory copy so proposals sharing a fingerprint dedup
+		tracker = support.NewMemoryTracker(issues...)
+	}
+
+	counts := make(map[string]int)
+	for _, proposal := range proposals {
+		action, err := support.FileProposal(tracker, proposal)
+		if err != nil {
+			return fmt.Errorf("failed to import %q: %w", proposal.Title, err)
+		}
+		counts[action.Kind]++
+		fmt.Printf("%-9s %s %s\n", action.Kind, action.Issue.ID, action.Issue.Title)
+	}
+
+	prefix := ""
+	if dryRun {
+		prefix = "Dry run: "
+	}
+	fmt.Printf("%s%d proposals: %d created, %d reopened, %d updated, %d adopted, %d unchanged\n",
+		prefix, len(proposals),
+		counts[support.ProposalCreate], counts[support.ProposalReopen], counts[support.ProposalUpdate],
+		counts[support.ProposalAdopt], counts[support.ProposalUnchanged])
+	return nil
+}
@@ -0,0 +1,60 @@
+// Command reconcile-a11y-issues closes the auto-created accessibility issues a
+// full-site scan (a11y-run.json with "full_site": true) no longer reproduces,
+// and reopens the ones it closed that regressed. Only issues on templates the
+// run scanned are closed; issues closed by hand stay closed.
+//
+//	go run ./cmd/reconcile-a11y-issues [-dry-run] [-force] [-jsonl ../.beads/issues.jsonl] a11y-run.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"pwarnock-tests/support"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "print what would change without touching the tracker")
+	force := flag.Bool("force", false, "reconcile a run that is not a full-site scan, on the templates it scanned")
+	jsonlPath := flag.String("jsonl", "", "edit this issues.jsonl directly instead of using the bd CLI")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: reconcile-a11y-issues [flags] <a11y-run.json>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), *jsonlPath, *dryRun, *force); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run reconciles the tracker against the saved run
+func run(runPath, jsonlPath string, dryRun, force bool) error {
+	scan, err := support.LoadA11yRun(runPath)
+	if err != nil {
+		return err
+	}
+	if len(scan.Templates) == 0 {
+		return fmt.Errorf("%s does not record which templates were scanned; save it again with this version of the suite", runPath)
+	}
+	if !scan.FullSite && !force {
+		return fmt.Errorf("%s is not a full-site scan (templates: %s); pass -force to reconcile only those templates",
+			runPath, strings.Join(scan.Templates, ", "))
+	}
+
+	var tracker support.IssueTracker = support.NewBdTracker()
+	if jsonlPath != "" {
+		tracker = support.NewJSONLTracker(jsonlPath)
+	}
+
+	_, err = support.Reconcile(tracker, scan, dryRun)
+	return err
+}
@@ -0,0 +1,44 @@
+Feature: Keyboard Navigation
+  Every control is reachable and usable with the keyboard alone, in a
+  sensible order and with a visible focus indicator
+
+  Scenario: Homepage focus order
+    Given I navigate to the "home" page
+    And the page should load successfully
+    When I tab through the page
+    Then the skip link should be the first focus stop
+    And every focus stop should have an accessible name
+    And every focus stop should show a visible focus indicator
+    And every interactive element in the navigation should be reachable by keyboard
+    And every interactive element in the carousel should be reachable by keyboard
+
+  Scenario: Tool cards are reachable by keyboard
+    Given I navigate to the "tools" page
+    And the page should load successfully
+    When I tab through the page
+    Then every focus stop should have an accessible name
+    And every interactive element in the cards should be reachable by keyboard
+
+  Scenario: Skip link moves focus to the main content
+    Given I navigate to the "home" page
+    And the page should load successfully
+    When I press "Tab"
+    And I press "Enter"
+    Then the main content should have focus
+
+  Scenario: Navigation links can be followed with Enter
+    Given I navigate to the "home" page
+    And the page should load successfully
+    When I tab to the "Blog" link
+    And I press Enter to follow the focused link
+    Then I should be on the "blog" page
+
+  Scenario: Mobile menu opens with Enter and closes with Escape
+    Given I navigate to the "home" page
+    And the page should load successfully
+    And I set the viewport to mobile size
+    When I tab to the "Toggle mobile navigation menu" button
+    And I press "Enter"
+    Then the mobile menu should be open
+    When I press "Escape"
+    Then the mobile menu should be closed
@@ -0,0 +1,40 @@
+Feature: Theme Color Contrast
+
+  Every theme marked accessible in data/themes.yaml must meet WCAG AA
+  color contrast on the site's key pages.
+
+  Scenario Outline: Theme persists across navigation
+    Given I navigate to the "home" page
+    And the "<theme>" theme is active
+    When I navigate to the "blog" page
+    Then the active theme should be "<theme>"
+
+    Examples:
+      | theme |
+      | light |
+      | dark  |
+
+  Scenario Outline: Core themes pass color contrast on <page>
+    Given I navigate to the "<page>" page
+    And the "<theme>" theme is active
+    Then the page should pass color contrast checks
+
+    Examples:
+      | page | theme |
+      | home | light |
+      | home | dark  |
+      | blog | light |
+      | blog | dark  |
+
+  Scenario Outline: All accessible themes pass color contrast on <page>
+    Given I navigate to the "<page>" page
+    And the page should load successfully
+    Then every accessible theme should pass color contrast checks
+
+    Examples:
+      | page      |
+      | home      |
+      | blog      |
+      | portfolio |
+      | tools     |
+      | about     |
@@ -0,0 +1,13 @@
+Feature: Accessibility Waivers
+  Known violations in a11y-waivers.toml are reported as waived instead of
+  failing, until the waiver expires
+
+  Scenario: No waiver has expired
+    Then no accessibility waiver should have expired
+
+  Scenario: Component demo meets WCAG 2.1 AA standards apart from waived violations
+    Given I navigate to the "components/piano-demo" page
+    And the page should load successfully
+    When I run WCAG 2.1 AA accessibility validation
+    Then I should see no critical accessibility violations
+    And I should see no serious accessibility violations
@@ -7,6 +7,13 @@ Feature: WCAG Compliance
     Then I should see no critical accessibility violations
     And I should see no serious accessibility violations
 
+  Scenario: Axe runs with third-party requests routed offline
+    Given I navigate to the "about" page
+    And the page should load successfully
+    When I run WCAG 2.1 AA accessibility validation
+    Then I should see no critical accessibility violations
+    And the page should make no requests to third-party domains
+
   Scenario: Blog page meets WCAG 2.1 AA standards
     Given I navigate to the "blog" page
     And the page should load successfully
@@ -19,4 +26,10 @@ Feature: WCAG Compliance
     And the page should load successfully
     When I run WCAG 2.1 AA accessibility validation
     Then I should see no critical accessibility violations
-    And I should see no serious accessibility violations
\ No newline at end of file
+    And I should see no serious accessibility violations
+
+  Scenario: Tool pages have no accessibility regressions against the baseline
+    Given I navigate to the "tools" page
+    And the page should load successfully
+    When I run WCAG 2.1 AA accessibility validation
+    Then there should be no new accessibility violations compared to baseline
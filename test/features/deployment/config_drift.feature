@@ -0,0 +1,10 @@
+Feature: Configuration Drift
+
+  Every environment layers config/<env>/hugo.toml over packages/site/hugo.toml.
+  Staging only tests what production ships if the merged configs agree on
+  everything except the differences listed in config-drift-policy.toml.
+
+  Scenario: Environments only differ where the drift policy allows
+    Given the config of every environment is merged over the site config
+    Then environments should only differ where the drift policy allows
+    And stray files in the config directories are reported
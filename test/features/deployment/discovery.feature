@@ -0,0 +1,23 @@
+Feature: Sitemap, robots.txt and Feeds
+
+  Hugo generates sitemap.xml and the RSS feeds; static/robots.txt is written
+  by hand. Crawlers and feed readers only see the site the way these files
+  describe it, so they are checked against the production build.
+
+  Background:
+    Given the "production" site is built
+
+  Scenario: Sitemap lists every page once with absolute production URLs
+    Then every sitemap URL should be absolute, unique and on the site host
+    And every sitemap URL should return 200
+
+  Scenario: robots.txt points crawlers at the sitemap
+    Then robots.txt should reference the sitemap
+    And robots.txt should not disallow any content section
+
+  Scenario: Blog feed lists the latest posts
+    Then the "blog/index.xml" feed should be valid
+    And the "blog/index.xml" feed should list the newest 10 posts in "blog"
+
+  Scenario: Site feed is valid
+    Then the "index.xml" feed should be valid
@@ -0,0 +1,17 @@
+Feature: Environment URL Isolation
+
+  Each environment is built with its own baseURL from config/<env>/hugo.toml.
+  Generated HTML, JSON and XML must not point at another environment:
+  no localhost or worktree ports outside development, no staging hosts in
+  production, no canonical URLs claiming to be production from staging, and
+  no livereload.js outside development.
+
+  Scenario Outline: <environment> build only references its own environment
+    Given the "<environment>" site is built
+    Then the build should not reference other environments
+
+    Examples:
+      | environment |
+      | development |
+      | staging     |
+      | production  |
@@ -0,0 +1,15 @@
+Feature: Unpublished Content
+
+  Content the environment's merged config does not build (drafts, expired
+  content, and future-dated content unless buildFuture is set) must not
+  reach any output format of a deployed build: HTML pages, sitemap.xml,
+  RSS feeds, index.json or tools/radar.json.
+
+  Scenario Outline: <environment> build publishes no unpublished content
+    Given the "<environment>" site is built
+    Then no unpublished content should appear in the build output
+
+    Examples:
+      | environment |
+      | staging     |
+      | production  |
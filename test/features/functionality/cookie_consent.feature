@@ -0,0 +1,50 @@
+@flag:cookie_consent_banner
+Feature: Cookie Consent
+
+  The cookie consent banner gates Google Tag Manager. Analytics hosts are
+  intercepted and stubbed, so these scenarios never reach the real tag
+  manager and can assert on what the page tried to load.
+
+  Scenario: Banner appears on first visit
+    Given the site is built for the "production" environment
+    And analytics requests are intercepted
+    When I navigate to the "home" page
+    Then the cookie consent banner should be visible
+    And no analytics requests should have been made
+
+  Scenario: Rejecting cookies prevents analytics on every page
+    Given the site is built for the "production" environment
+    And analytics requests are intercepted
+    And I navigate to the "home" page
+    When I reject non-essential cookies
+    Then cookie consent should be stored as rejected
+    When I navigate to the "blog" page
+    Then the cookie consent banner should not be visible
+    When I navigate to the "about" page
+    Then the cookie consent banner should not be visible
+    And no analytics requests should have been made
+
+  Scenario: Accepting cookies persists across pages
+    Given the site is built for the "production" environment
+    And analytics requests are intercepted
+    And I navigate to the "home" page
+    When I accept cookies
+    Then cookie consent should be stored as accepted
+    And analytics requests should have been made
+    When I navigate to the "blog" page
+    Then the cookie consent banner should not be visible
+    And cookie consent should be stored as accepted
+
+  Scenario Outline: <environment> builds never load analytics
+    Given the site is built for the "<environment>" environment
+    And analytics requests are intercepted
+    And I navigate to the "home" page
+    When I accept cookies
+    And I navigate to the "blog" page
+    Then cookie consent should be stored as accepted
+    And no analytics requests should have been made
+
+    Examples:
+      | environment |
+      | development |
+      | staging     |
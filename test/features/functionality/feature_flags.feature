@@ -0,0 +1,36 @@
+Feature: Feature Flags
+
+  Scenarios tagged @flag:<name> only run when the flag in
+  data/feature-flags.toml is enabled, and @flag-off:<name> only when it is
+  disabled. Override flags for a run with FEATURE_FLAGS="name=on,other=off".
+
+  @flag:cookie_consent_banner
+  Scenario: Cookie consent banner is shown when its flag is enabled
+    Given I navigate to the "home" page
+    And the page should load successfully
+    Then the cookie consent banner should be visible
+
+  @flag-off:books_section
+  Scenario: Books section is hidden when its flag is disabled
+    Given I navigate to the "about" page
+    And the page should load successfully
+    Then the page should not have a "Books That Shaped Me" heading
+
+  Scenario Outline: About page builds with books section <state>
+    Given the site is built with the "books_section" flag <state>
+    When I navigate to the "about" page
+    Then the page should load successfully
+    And the page <expectation> have a "Books That Shaped Me" heading
+    And there should be no JavaScript errors
+
+    Examples:
+      | state    | expectation |
+      | enabled  | should      |
+      | disabled | should not  |
+
+  Scenario: Deployed production config loads analytics without a consent banner
+    Given the site is built for the "production" environment as deployed
+    And analytics requests are intercepted
+    When I navigate to the "home" page
+    Then the cookie consent banner should not be visible
+    And analytics requests should have been made
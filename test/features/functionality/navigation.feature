@@ -15,4 +15,31 @@ Feature: Navigation and Accessibility
   Scenario: Homepage accessibility validation
     Given I navigate to the "home" page
     And the page should load successfully
-    And I should see no accessibility violations
\ No newline at end of file
+    And I should see no accessibility violations
+
+  Scenario: Homepage loads without JavaScript errors
+    Given I navigate to the "home" page
+    And the page should load successfully
+    Then there should be no JavaScript errors
+    And there should be no failed network requests
+
+  Scenario: Homepage is served as HTML without redirects
+    Given I navigate to the "home" page
+    Then the response status should be 200
+    And the response content type should be "text/html"
+    And the page should not have been redirected
+
+  Scenario: Missing pages render the not found layout
+    Given I navigate to the "this-page-does-not-exist" page
+    Then I should see the not found page
+
+  Scenario: Navigate to blog page from the mobile menu
+    Given I navigate to the "home" page
+    And I set viewport to mobile size
+    When I click the "Blog" link in navigation
+    Then I should be on the "blog" page
+
+  Scenario: Homepage loads without third-party requests
+    Given I navigate to the "home" page
+    And the page should load successfully
+    Then the page should make no requests to third-party domains
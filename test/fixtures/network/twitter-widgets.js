@@ -0,0 +1,2 @@
+/* X/Twitter widgets.js stub served by the test network router */
+window.twttr = window.twttr || { widgets: { load: function() {} }, ready: function(fn) { fn(window.twttr); } };
@@ -3,10 +3,12 @@ module pwarnock-tests
 go 1.24.0
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/cucumber/godog v0.12.0
 	github.com/playwright-community/playwright-go v0.5200.1
 	github.com/pwarnock/go-playwright-testkit v0.0.0-20260127081758-283c00713e25
 	github.com/stretchr/testify v1.11.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -42,5 +44,4 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20251111163417-95abcf5c77ba // indirect
 	google.golang.org/grpc v1.75.0 // indirect
 	google.golang.org/protobuf v1.36.10 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
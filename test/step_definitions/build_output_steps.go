@@ -0,0 +1,285 @@
+package step_definitions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cucumber/godog"
+	"pwarnock-tests/support"
+)
+
+// BuildOutputSteps implements checks on the files generated by a site build
+type BuildOutputSteps struct {
+	testCtx *support.TestContext
+}
+
+// NewBuildOutputSteps creates a new BuildOutputSteps instance
+func NewBuildOutputSteps(ctx *support.TestContext) *BuildOutputSteps {
+	return &BuildOutputSteps{
+		testCtx: ctx,
+	}
+}
+
+// SetTestContext sets the test context (for delayed initialization)
+func (bs *BuildOutputSteps) SetTestContext(ctx *support.TestContext) {
+	bs.testCtx = ctx
+}
+
+// RegisterSteps registers all build output steps with the scenario context
+func (bs *BuildOutputSteps) RegisterSteps(ctx *godog.ScenarioContext) {
+	ctx.Step(`^the build should not reference other environments$`, bs.theBuildShouldNotReferenceOtherEnvironments)
+	ctx.Step(`^no unpublished content should appear in the build output$`, bs.noUnpublishedContentShouldAppearInTheBuildOutput)
+	ctx.Step(`^every sitemap URL should be absolute, unique and on the site host$`, bs.everySitemapURLShouldBeAbsoluteUniqueAndOnTheSiteHost)
+	ctx.Step(`^every sitemap URL should return 200$`, bs.everySitemapURLShouldReturn200)
+	ctx.Step(`^robots\.txt should reference the sitemap$`, bs.robotsTxtShouldReferenceTheSitemap)
+	ctx.Step(`^robots\.txt should not disallow any content section$`, bs.robotsTxtShouldNotDisallowAnyContentSection)
+	ctx.Step(`^the "([^"]*)" feed should be valid$`, bs.theFeedShouldBeValid)
+	ctx.Step(`^the "([^"]*)" feed should list the newest (\d+) posts in "([^"]*)"$`, bs.theFeedShouldListTheNewestPosts)
+}
+
+// build returns the static build under inspection
+func (bs *BuildOutputSteps) build() (*support.BuiltSite, error) {
+	if bs.testCtx.Build == nil {
+		return nil, fmt.Errorf(`no site build to inspect - add 'Given the "production" site is built'`)
+	}
+	return bs.testCtx.Build, nil
+}
+
+// theBuildShouldNotReferenceOtherEnvironments scans for localhost, worktree
+// ports, other environments' hosts and livereload.js
+func (bs *BuildOutputSteps) theBuildShouldNotReferenceOtherEnvironments() error {
+	site, err := bs.build()
+	if err != nil {
+		return err
+	}
+
+	baseURLs, err := support.EnvironmentBaseURLs()
+	if err != nil {
+		return err
+	}
+
+	detector, err := support.NewLeakDetector(site.Environment, baseURLs)
+	if err != nil {
+		return err
+	}
+
+	leaks, err := detector.ScanBuildForLeaks(site.Dir)
+	if err != nil {
+		return err
+	}
+
+	if len(leaks) > 0 {
+		return fmt.Errorf("%s build references other environments (%d findings):\n%s",
+			site.Environment, len(leaks), support.SummarizeLeaks(leaks, 50))
+	}
+	return nil
+}
+
+// noUnpublishedContentShouldAppearInTheBuildOutput cross-references draft,
+// future-dated and expired frontmatter against every output format
+func (bs *BuildOutputSteps) noUnpublishedContentShouldAppearInTheBuildOutput() error {
+	site, err := bs.build()
+	if err != nil {
+		return err
+	}
+
+	items, err := support.LoadContent(support.ContentDir)
+	if err != nil {
+		return err
+	}
+
+	opts, err := support.EnvironmentPublishOptions(site.Environment)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	unpublished := 0
+	for _, item := range items {
+		if !item.PublishedWith(now, opts) {
+			unpublished++
+		}
+	}
+	bs.testCtx.Logf("Checking %d unpublished of %d content files against the %s build",
+		unpublished, len(items), site.Environment)
+
+	leaks, err := support.FindContentLeaks(site, items, now)
+	if err != nil {
+		return err
+	}
+
+	if len(leaks) > 0 {
+		lines := make([]string, 0, len(leaks))
+		for _, leak := range leaks {
+			lines = append(lines, "  - "+leak.String())
+		}
+		return fmt.Errorf("%d unpublished content files leaked into the %s build:\n%s",
+			len(leaks), site.Environment, strings.Join(lines, "\n"))
+	}
+	return nil
+}
+
+// sitemapURLs loads the build's sitemap, failing if it lists nothing
+func (bs *BuildOutputSteps) sitemapURLs() (*support.BuiltSite, []string, error) {
+	site, err := bs.build()
+	if err != nil {
+		return nil, nil, err
+	}
+	urls, err := support.LoadSitemapURLs(site)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(urls) == 0 {
+		return nil, nil, fmt.Errorf("%s lists no URLs", support.SitemapPath)
+	}
+	return site, urls, nil
+}
+
+// everySitemapURLShouldBeAbsoluteUniqueAndOnTheSiteHost checks each <loc>
+// against the environment's baseURL
+func (bs *BuildOutputSteps) everySitemapURLShouldBeAbsoluteUniqueAndOnTheSiteHost() error {
+	site, urls, err := bs.sitemapURLs()
+	if err != nil {
+		return err
+	}
+	bs.testCtx.Logf("Checking %d sitemap URLs", len(urls))
+
+	if problems := support.CheckSitemapURLs(urls, site.BaseURL); len(problems) > 0 {
+		return fmt.Errorf("%d invalid sitemap entries:\n  - %s", len(problems), strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// everySitemapURLShouldReturn200 serves the build locally and requests the
+// path of every sitemap URL
+func (bs *BuildOutputSteps) everySitemapURLShouldReturn200() error {
+	site, urls, err := bs.sitemapURLs()
+	if err != nil {
+		return err
+	}
+
+	server := support.NewStaticServer(site.Dir)
+	defer server.Close()
+
+	if problems := support.CheckURLStatuses(nil, server.URL(), urls); len(problems) > 0 {
+		return fmt.Errorf("%d of %d sitemap URLs do not return 200:\n  - %s",
+			len(problems), len(urls), strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// robotsTxtShouldReferenceTheSitemap expects a Sitemap line for the build's own sitemap
+func (bs *BuildOutputSteps) robotsTxtShouldReferenceTheSitemap() error {
+	site, err := bs.build()
+	if err != nil {
+		return err
+	}
+	robots, err := support.LoadRobots(site)
+	if err != nil {
+		return err
+	}
+
+	expected := strings.TrimSuffix(site.BaseURL, "/") + "/" + support.SitemapPath
+	for _, sitemap := range robots.Sitemaps {
+		if sitemap == expected {
+			return nil
+		}
+	}
+	return fmt.Errorf("robots.txt should contain \"Sitemap: %s\", found %v", expected, robots.Sitemaps)
+}
+
+// robotsTxtShouldNotDisallowAnyContentSection checks every published section
+// URL is crawlable by generic crawlers
+func (bs *BuildOutputSteps) robotsTxtShouldNotDisallowAnyContentSection() error {
+	site, err := bs.build()
+	if err != nil {
+		return err
+	}
+	robots, err := support.LoadRobots(site)
+	if err != nil {
+		return err
+	}
+	items, err := support.LoadContent(support.ContentDir)
+	if err != nil {
+		return err
+	}
+	opts, err := support.EnvironmentPublishOptions(site.Environment)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var blocked []string
+	for _, item := range items {
+		if !item.IsSection() || !item.PublishedWith(now, opts) {
+			continue
+		}
+		if !robots.Allowed("*", item.URLPath()) {
+			blocked = append(blocked, item.URLPath())
+		}
+	}
+
+	if len(blocked) > 0 {
+		return fmt.Errorf("robots.txt disallows content sections: %s", strings.Join(blocked, ", "))
+	}
+	return nil
+}
+
+// theFeedShouldBeValid parses an RSS or Atom feed and checks its links and dates
+func (bs *BuildOutputSteps) theFeedShouldBeValid(rel string) error {
+	site, err := bs.build()
+	if err != nil {
+		return err
+	}
+	feed, err := support.LoadFeed(site, rel)
+	if err != nil {
+		return err
+	}
+	if len(feed.Items) == 0 {
+		return fmt.Errorf("feed %s has no items", rel)
+	}
+	bs.testCtx.Logf("Feed %s (%s) has %d items", rel, feed.Format, len(feed.Items))
+
+	if problems := feed.Check(site.BaseURL); len(problems) > 0 {
+		return fmt.Errorf("feed %s has %d problems:\n  - %s", rel, len(problems), strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// theFeedShouldListTheNewestPosts compares the feed with the section's
+// published content, newest first
+func (bs *BuildOutputSteps) theFeedShouldListTheNewestPosts(rel string, n int, section string) error {
+	site, err := bs.build()
+	if err != nil {
+		return err
+	}
+	feed, err := support.LoadFeed(site, rel)
+	if err != nil {
+		return err
+	}
+	items, err := support.LoadContent(support.ContentDir)
+	if err != nil {
+		return err
+	}
+	opts, err := support.EnvironmentPublishOptions(site.Environment)
+	if err != nil {
+		return err
+	}
+
+	posts := support.FeedPosts(items, section, time.Now(), opts)
+	if len(posts) == 0 {
+		return fmt.Errorf("no published posts in %s", section)
+	}
+
+	missing := support.MissingFromFeed(feed, posts, n)
+	if len(missing) > 0 {
+		lines := make([]string, 0, len(missing))
+		for _, post := range missing {
+			lines = append(lines, fmt.Sprintf("  - %s (%s)", post.Path, post.URLPath()))
+		}
+		return fmt.Errorf("feed %s is missing %d of the newest %d posts in %s:\n%s",
+			rel, len(missing), n, section, strings.Join(lines, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package step_definitions
+
+import (
+	"fmt"
+
+	"github.com/cucumber/godog"
+	"pwarnock-tests/support"
+)
+
+// ConfigDriftSteps implements checks on how environment configs differ
+type ConfigDriftSteps struct {
+	testCtx *support.TestContext
+	diffs   []support.ConfigDifference
+}
+
+// NewConfigDriftSteps creates a new ConfigDriftSteps instance
+func NewConfigDriftSteps(ctx *support.TestContext) *ConfigDriftSteps {
+	return &ConfigDriftSteps{
+		testCtx: ctx,
+	}
+}
+
+// SetTestContext sets the test context (for delayed initialization)
+func (cs *ConfigDriftSteps) SetTestContext(ctx *support.TestContext) {
+	cs.testCtx = ctx
+	cs.diffs = nil
+}
+
+// RegisterSteps registers all config drift steps with the scenario context
+func (cs *ConfigDriftSteps) RegisterSteps(ctx *godog.ScenarioContext) {
+	ctx.Step(`^the config of every environment is merged over the site config$`, cs.theConfigOfEveryEnvironmentIsMerged)
+	ctx.Step(`^environments should only differ where the drift policy allows$`, cs.environmentsShouldOnlyDifferWhereAllowed)
+	ctx.Step(`^stray files in the config directories are reported$`, cs.strayFilesAreReported)
+}
+
+// theConfigOfEveryEnvironmentIsMerged loads and diffs every config/<env>/hugo.toml
+func (cs *ConfigDriftSteps) theConfigOfEveryEnvironmentIsMerged() error {
+	diffs, err := support.EnvironmentConfigDrift()
+	if err != nil {
+		return err
+	}
+	cs.diffs = diffs
+	return nil
+}
+
+// environmentsShouldOnlyDifferWhereAllowed prints the full semantic diff and
+// fails on keys the policy does not allow to differ
+func (cs *ConfigDriftSteps) environmentsShouldOnlyDifferWhereAllowed() error {
+	policy, err := support.LoadConfigDriftPolicy(support.ConfigDriftPolicyPath)
+	if err != nil {
+		return err
+	}
+
+	unexpected := policy.Apply(cs.diffs)
+	cs.testCtx.Logf("Config drift between environments (%d keys):\n%s",
+		len(cs.diffs), support.FormatConfigDrift(cs.diffs))
+
+	if len(unexpected) > 0 {
+		return fmt.Errorf("%d config keys differ between environments without an entry in %s:\n%s",
+			len(unexpected), support.ConfigDriftPolicyPath, support.FormatConfigDrift(unexpected))
+	}
+	return nil
+}
+
+// strayFilesAreReported notes backups and other files Hugo ignores; they
+// never affect a build, so they are reported rather than failed
+func (cs *ConfigDriftSteps) strayFilesAreReported() error {
+	stray, err := support.StrayConfigFiles()
+	if err != nil {
+		return err
+	}
+	for _, file := range stray {
+		cs.testCtx.Logf("Note: %s is not a config file Hugo reads and can be deleted", file)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package step_definitions
+
+import (
+	"fmt"
+
+	"github.com/cucumber/godog"
+	"github.com/playwright-community/playwright-go"
+	"pwarnock-tests/support"
+)
+
+// ConsentSteps implements cookie consent and analytics gating steps
+type ConsentSteps struct {
+	testCtx *support.TestContext
+}
+
+// NewConsentSteps creates a new ConsentSteps instance
+func NewConsentSteps(ctx *support.TestContext) *ConsentSteps {
+	return &ConsentSteps{
+		testCtx: ctx,
+	}
+}
+
+// SetTestContext sets the test context (for delayed initialization)
+func (cs *ConsentSteps) SetTestContext(ctx *support.TestContext) {
+	cs.testCtx = ctx
+}
+
+// RegisterSteps registers all consent steps with the scenario context
+func (cs *ConsentSteps) RegisterSteps(ctx *godog.ScenarioContext) {
+	ctx.Step(`^analytics requests are intercepted$`, cs.analyticsRequestsAreIntercepted)
+	ctx.Step(`^the cookie consent banner should be visible$`, cs.theCookieConsentBannerShouldBeVisible)
+	ctx.Step(`^the cookie consent banner should not be visible$`, cs.theCookieConsentBannerShouldNotBeVisible)
+	ctx.Step(`^I accept cookies$`, cs.iAcceptCookies)
+	ctx.Step(`^I reject non-essential cookies$`, cs.iRejectNonEssentialCookies)
+	ctx.Step(`^cookie consent should be stored as (accepted|rejected)$`, cs.cookieConsentShouldBeStoredAs)
+	ctx.Step(`^no analytics requests should have been made$`, cs.noAnalyticsRequestsShouldHaveBeenMade)
+	ctx.Step(`^analytics requests should have been made$`, cs.analyticsRequestsShouldHaveBeenMade)
+}
+
+// analyticsRequestsAreIntercepted starts network routing before the first page
+// loads, so analytics hosts are stubbed and recorded from the start
+func (cs *ConsentSteps) analyticsRequestsAreIntercepted() error {
+	return cs.testCtx.PreparePage()
+}
+
+// banner returns the cookie consent banner locator
+func (cs *ConsentSteps) banner() (playwright.Locator, error) {
+	if cs.testCtx.Browser == nil {
+		return nil, fmt.Errorf("browser not initialized - navigation step should run first")
+	}
+	return cs.testCtx.Page().Locator(support.CookieConsentBanner), nil
+}
+
+// theCookieConsentBannerShouldBeVisible waits for the banner to be shown
+func (cs *ConsentSteps) theCookieConsentBannerShouldBeVisible() error {
+	banner, err := cs.banner()
+	if err != nil {
+		return err
+	}
+
+	if err := banner.WaitFor(playwright.LocatorWaitForOptions{
+		State: playwright.WaitForSelectorStateVisible,
+	}); err != nil {
+		return fmt.Errorf("cookie consent banner is not visible: %w", err)
+	}
+
+	return nil
+}
+
+// theCookieConsentBannerShouldNotBeVisible checks the banner is hidden or not rendered
+func (cs *ConsentSteps) theCookieConsentBannerShouldNotBeVisible() error {
+	banner, err := cs.banner()
+	if err != nil {
+		return err
+	}
+
+	if err := banner.WaitFor(playwright.LocatorWaitForOptions{
+		State: playwright.WaitForSelectorStateHidden,
+	}); err != nil {
+		return fmt.Errorf("cookie consent banner is still visible: %w", err)
+	}
+
+	return nil
+}
+
+// clickBannerButton clicks a banner action by its accessible name
+func (cs *ConsentSteps) clickBannerButton(name string) error {
+	banner, err := cs.banner()
+	if err != nil {
+		return err
+	}
+
+	button := banner.GetByRole(*playwright.AriaRoleButton, playwright.LocatorGetByRoleOptions{
+		Name:  name,
+		Exact: playwright.Bool(true),
+	})
+	if err := button.Click(); err != nil {
+		return fmt.Errorf("could not click %q in cookie consent banner: %w", name, err)
+	}
+
+	return cs.theCookieConsentBannerShouldNotBeVisible()
+}
+
+// iAcceptCookies accepts all cookies from the banner
+func (cs *ConsentSteps) iAcceptCookies() error {
+	return cs.clickBannerButton("Accept All")
+}
+
+// iRejectNonEssentialCookies chooses essential cookies only
+func (cs *ConsentSteps) iRejectNonEssentialCookies() error {
+	return cs.clickBannerButton("Essential Only")
+}
+
+// cookieConsentShouldBeStoredAs checks the persisted consent record
+func (cs *ConsentSteps) cookieConsentShouldBeStoredAs(state string) error {
+	if cs.testCtx.Browser == nil {
+		return fmt.Errorf("browser not initialized - navigation step should run first")
+	}
+
+	consent, err := support.StoredConsent(cs.testCtx.Page())
+	if err != nil {
+		return err
+	}
+	if consent == nil {
+		return fmt.Errorf("no cookie consent stored on %s", cs.testCtx.Page().URL())
+	}
+
+	accepted := state == "accepted"
+	if consent.Analytics != accepted || consent.Marketing != accepted {
+		return fmt.Errorf("expected consent to be %s but stored analytics=%t marketing=%t",
+			state, consent.Analytics, consent.Marketing)
+	}
+
+	return nil
+}
+
+// analyticsRequests returns the analytics requests recorded by the network router
+func (cs *ConsentSteps) analyticsRequests() ([]support.NetworkRequest, error) {
+	if cs.testCtx.Network == nil {
+		return nil, fmt.Errorf("analytics are not intercepted - add \"Given analytics requests are intercepted\"")
+	}
+	return cs.testCtx.Network.RequestsMatching(support.AnalyticsURLPattern), nil
+}
+
+// noAnalyticsRequestsShouldHaveBeenMade fails if any analytics host was requested
+func (cs *ConsentSteps) noAnalyticsRequestsShouldHaveBeenMade() error {
+	requests, err := cs.analyticsRequests()
+	if err != nil {
+		return err
+	}
+
+	if len(requests) > 0 {
+		return fmt.Errorf("expected no analytics requests but found %d:\n%s",
+			len(requests), support.FormatRequests(requests))
+	}
+
+	return nil
+}
+
+// analyticsRequestsShouldHaveBeenMade checks analytics loaded after consent
+func (cs *ConsentSteps) analyticsRequestsShouldHaveBeenMade() error {
+	requests, err := cs.analyticsRequests()
+	if err != nil {
+		return err
+	}
+
+	if len(requests) == 0 {
+		return fmt.Errorf("expected analytics requests but none were made")
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package step_definitions
+
+import (
+	"fmt"
+
+	"github.com/cucumber/godog"
+	"pwarnock-tests/support"
+)
+
+// FeatureFlagSteps implements steps for data/feature-flags.toml
+type FeatureFlagSteps struct {
+	testCtx *support.TestContext
+	flags   support.FeatureFlags
+}
+
+// NewFeatureFlagSteps creates a new FeatureFlagSteps instance
+func NewFeatureFlagSteps(ctx *support.TestContext, flags support.FeatureFlags) *FeatureFlagSteps {
+	return &FeatureFlagSteps{
+		testCtx: ctx,
+		flags:   flags,
+	}
+}
+
+// SetTestContext sets the test context (for delayed initialization)
+func (fs *FeatureFlagSteps) SetTestContext(ctx *support.TestContext) {
+	fs.testCtx = ctx
+}
+
+// RegisterSteps registers all feature flag steps with the scenario context
+func (fs *FeatureFlagSteps) RegisterSteps(ctx *godog.ScenarioContext) {
+	ctx.Step(`^the "([^"]*)" feature flag should be (enabled|disabled)$`, fs.theFeatureFlagShouldBe)
+	ctx.Step(`^the site is built with the "([^"]*)" flag (enabled|disabled)$`, fs.theSiteIsBuiltWithTheFlag)
+	ctx.Step(`^the site is built for the "([^"]*)" environment$`, fs.theSiteIsBuiltForTheEnvironment)
+	ctx.Step(`^the site is built for the "([^"]*)" environment as deployed$`, fs.theSiteIsBuiltForTheEnvironmentAsDeployed)
+	ctx.Step(`^the "([^"]*)" site is built$`, fs.theSiteIsBuilt)
+}
+
+// theFeatureFlagShouldBe checks the effective value of a flag for this run
+func (fs *FeatureFlagSteps) theFeatureFlagShouldBe(name, state string) error {
+	if _, ok := fs.flags[name]; !ok {
+		return fmt.Errorf("unknown feature flag %q", name)
+	}
+
+	want := state == "enabled"
+	if fs.flags.IsEnabled(name) != want {
+		return fmt.Errorf("expected feature flag %s to be %s", name, state)
+	}
+
+	return nil
+}
+
+// theSiteIsBuiltWithTheFlag builds the development site with one flag forced
+// on or off and serves it, so the following steps exercise that branch
+func (fs *FeatureFlagSteps) theSiteIsBuiltWithTheFlag(name, state string) error {
+	flags, err := fs.flags.WithOverrides(map[string]bool{name: state == "enabled"})
+	if err != nil {
+		return err
+	}
+
+	fs.testCtx.Logf("Building site with %s %s", name, state)
+	server, err := support.BuildAndServe("development", flags)
+	if err != nil {
+		return err
+	}
+
+	fs.testCtx.ServeBuild(server)
+	return nil
+}
+
+// theSiteIsBuiltForTheEnvironment builds and serves one environment's config
+// with the run's effective flags
+func (fs *FeatureFlagSteps) theSiteIsBuiltForTheEnvironment(environment string) error {
+	fs.testCtx.Logf("Building site for %s", environment)
+	server, err := support.BuildAndServe(environment, fs.flags)
+	if err != nil {
+		return err
+	}
+
+	fs.testCtx.ServeBuild(server)
+	return nil
+}
+
+// theSiteIsBuiltForTheEnvironmentAsDeployed builds and serves one
+// environment's committed config without the harness's flags layer, so
+// templates read data/feature-flags.toml as they do in a real deploy
+func (fs *FeatureFlagSteps) theSiteIsBuiltForTheEnvironmentAsDeployed(environment string) error {
+	fs.testCtx.Logf("Building site for %s as deployed", environment)
+	server, err := support.BuildAndServe(environment, nil)
+	if err != nil {
+		return err
+	}
+
+	fs.testCtx.ServeBuild(server)
+	return nil
+}
+
+// theSiteIsBuilt builds an environment with its own baseURL for checks on
+// the generated files rather than in the browser
+func (fs *FeatureFlagSteps) theSiteIsBuilt(environment string) error {
+	fs.testCtx.Logf("Building %s site for inspection", environment)
+	site, err := support.BuildSite(environment, fs.flags)
+	if err != nil {
+		return err
+	}
+
+	fs.testCtx.UseBuild(site)
+	return nil
+}
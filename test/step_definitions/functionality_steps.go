@@ -3,9 +3,9 @@ package step_definitions
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/cucumber/godog"
+	"github.com/playwright-community/playwright-go"
 	"github.com/pwarnock/go-playwright-testkit/pkg/browser"
 	"github.com/pwarnock/go-playwright-testkit/pkg/logger"
 	"pwarnock-tests/support"
@@ -13,8 +13,9 @@ import (
 
 // NavigationSteps implements navigation-related BDD steps
 type NavigationSteps struct {
-	testCtx *support.TestContext
-	browser *browser.Browser
+	testCtx  *support.TestContext
+	browser  *browser.Browser
+	response *support.NavigationResult
 }
 
 // NewNavigationSteps creates a new NavigationSteps instance
@@ -41,28 +42,38 @@ func (ns *NavigationSteps) RegisterSteps(ctx *godog.ScenarioContext) {
 	ctx.Step(`^I should be on "([^"]*)" page$`, ns.iShouldBeOnPage)
 	ctx.Step(`^I should be on "([^"]*)" page$`, ns.iShouldBeOnPage)
 	ctx.Step(`^I should be on the "([^"]*)" page$`, ns.iShouldBeOnPage)
+	ctx.Step(`^there should be no JavaScript errors$`, ns.thereShouldBeNoJavaScriptErrors)
+	ctx.Step(`^there should be no failed network requests$`, ns.thereShouldBeNoFailedNetworkRequests)
+	ctx.Step(`^the response status should be (\d+)$`, ns.theResponseStatusShouldBe)
+	ctx.Step(`^the response content type should be "([^"]*)"$`, ns.theResponseContentTypeShouldBe)
+	ctx.Step(`^the page should not have been redirected$`, ns.thePageShouldNotHaveBeenRedirected)
+	ctx.Step(`^I should have been redirected to the "([^"]*)" page$`, ns.iShouldHaveBeenRedirectedToThePage)
+	ctx.Step(`^I should see the not found page$`, ns.iShouldSeeTheNotFoundPage)
+	ctx.Step(`^the page (should|should not) have a "([^"]*)" heading$`, ns.thePageShouldHaveAHeading)
 }
 
 // iNavigateToThePage navigates to a specific page
 func (ns *NavigationSteps) iNavigateToThePage(pageName string) error {
 	// Create browser instance if not exists
-	if ns.browser == nil {
-		browser, err := browser.NewBrowser()
-		if err != nil {
-			return fmt.Errorf("failed to create browser: %w", err)
-		}
-		ns.browser = browser
-		// Store browser in test context so other step definitions can access it
-		ns.testCtx.Browser = browser
+	if err := ns.testCtx.PreparePage(); err != nil {
+		return err
 	}
+	// Keep a reference so other steps can check the browser is initialized
+	ns.browser = ns.testCtx.Browser
 
 	// Navigate to page
 	url := ns.testCtx.GetPageURL(pageName)
-	err := ns.browser.NavigateTo(url)
+	resp, err := ns.testCtx.Page().Goto(url)
 	if err != nil {
 		return fmt.Errorf("could not navigate to %s: %w", url, err)
 	}
 
+	// Keep the main-document response for status and redirect assertions
+	ns.response, err = support.NewNavigationResult(resp)
+	if err != nil {
+		return fmt.Errorf("could not read response for %s: %w", url, err)
+	}
+
 	return nil
 }
 
@@ -73,7 +84,7 @@ func (ns *NavigationSteps) thePageShouldLoadSuccessfully() error {
 	}
 
 	// Check if we have a valid page title
-	page := ns.browser.GetPage()
+	page := ns.testCtx.Page()
 	title, err := page.Title()
 	if err != nil {
 		return fmt.Errorf("could not get page title: %w", err)
@@ -83,14 +94,20 @@ func (ns *NavigationSteps) thePageShouldLoadSuccessfully() error {
 		return fmt.Errorf("page title is empty - page may not have loaded properly")
 	}
 
-	// Check if we're not on an error page
-	url := ns.browser.GetURL()
-	if url == "" {
-		return fmt.Errorf("could not get current URL")
+	// Check the main-document response when the last step navigated directly
+	if ns.response != nil {
+		if err := ns.response.CheckSuccess(); err != nil {
+			return fmt.Errorf("page did not load successfully: %w", err)
+		}
 	}
 
-	if strings.Contains(url, "404") || strings.Contains(url, "error") {
-		return fmt.Errorf("page appears to be an error page: %s", url)
+	// Static hosts may serve the 404 layout with a 200 status
+	notFound, err := support.IsNotFoundPage(page)
+	if err != nil {
+		return err
+	}
+	if notFound {
+		return fmt.Errorf("page rendered the not found layout: %s", page.URL())
 	}
 
 	return nil
@@ -102,98 +119,60 @@ func (ns *NavigationSteps) iShouldSeeNoAccessibilityViolations() error {
 		return fmt.Errorf("browser not initialized")
 	}
 
-	page := ns.browser.GetPage()
-
-	// Inject axe into the page
-	_, err := page.Evaluate(`(function() {
-		return new Promise((resolve, reject) => {
-			if (typeof axe === 'undefined') {
-				const script = document.createElement('script');
-				script.src = 'https://cdnjs.cloudflare.com/ajax/libs/axe-core/4.8.2/axe.min.js';
-				script.onload = () => resolve();
-				script.onerror = () => reject(new Error('Failed to load axe-core'));
-				document.head.appendChild(script);
-			} else {
-				resolve();
-			}
-		});
-	})()`)
+	result, err := support.RunAxeWithWaivers(ns.testCtx.Page(), support.WCAGTags)
 	if err != nil {
-		return fmt.Errorf("failed to inject axe: %w", err)
+		return err
 	}
-
-	// Wait for axe to load
-	err = ns.browser.WaitForFunction("() => typeof axe !== 'undefined'")
-	if err != nil {
-		return fmt.Errorf("axe did not load: %w", err)
+	if result.WaivedNodes() > 0 {
+		ns.testCtx.Logf("%d accessibility nodes waived on %s:%s", result.WaivedNodes(), result.URL, result.WaivedSummary())
 	}
-
-	// Run axe
-	result, err := page.Evaluate(`axe.run(document, {
-		reporter: 'v2',
-		runOnly: {
-			type: 'tag',
-			values: ['wcag2a', 'wcag2aa', 'wcag21aa']
-		}
-	})`)
-	if err != nil {
-		return fmt.Errorf("failed to run axe: %w", err)
+	if err := result.ExpiredError(); err != nil {
+		return err
 	}
 
 	// Check for violations
-	if resultMap, ok := result.(map[string]interface{}); ok {
-		if violations, ok := resultMap["violations"].([]interface{}); ok {
-			if len(violations) > 0 {
-				// Use structured logging for accessibility violations
-				if sl, ok := ns.testCtx.StructuredLogger.(*logger.StructuredLogger); ok && sl != nil {
-					sl.LogAccessibility(violations)
-				}
-				return fmt.Errorf("found %d accessibility violations", len(violations))
+	violations := result.Violations
+	if len(violations) > 0 {
+		// Use structured logging for accessibility violations
+		if sl, ok := ns.testCtx.StructuredLogger.(*logger.StructuredLogger); ok && sl != nil {
+			logged := make([]interface{}, len(violations))
+			for i, v := range violations {
+				logged[i] = v
 			}
+			sl.LogAccessibility(logged)
 		}
+		return fmt.Errorf("found %d accessibility violations (%d nodes waived):%s", len(violations), result.WaivedNodes(), support.SummarizeViolationsWithSources(result.URL, violations))
 	}
 
 	return nil
 }
 
-// iClickTheLinkInNavigation clicks a navigation link
+// iClickTheLinkInNavigation clicks a navigation link by its accessible name,
+// opening the mobile menu first when the viewport hides the desktop menubar
 func (ns *NavigationSteps) iClickTheLinkInNavigation(linkText string) error {
 	if ns.browser == nil {
 		return fmt.Errorf("browser not initialized")
 	}
 
-	// Try different selectors for navigation links (mobile and desktop)
-	selectors := []string{
-		// Simple text-based selectors
-		fmt.Sprintf(`text=%s`, linkText),
-		// Desktop navigation (links in menu)
-		fmt.Sprintf(`.menu-horizontal a:has-text("%s")`, linkText),
-		fmt.Sprintf(`nav .menu-horizontal a:has-text("%s")`, linkText),
-		// Mobile navigation (dropdown menu)
-		fmt.Sprintf(`nav .dropdown-content a:has-text("%s")`, linkText),
-		fmt.Sprintf(`.dropdown-content a:has-text("%s")`, linkText),
-		// Fallback with href
-		fmt.Sprintf(`a[href*="%s"]`, strings.ToLower(linkText)),
-	}
-
-	var lastErr error
-	for _, selector := range selectors {
-		// Wait for element to be available
-		err := ns.browser.WaitForSelector(selector)
-		if err == nil {
-			// Element found, click it
-			err = ns.browser.ClickElement(selector)
-			if err == nil {
-				// Wait a moment for navigation to start
-				time.Sleep(100 * time.Millisecond)
-				return nil
-			}
-		} else {
-			lastErr = err
+	link, err := support.NewNavigationMenu(ns.testCtx.Page()).FindLink(linkText)
+	if err != nil {
+		return fmt.Errorf("could not find navigation link '%s': %w", linkText, err)
+	}
+
+	resp, err := support.ClickAndWaitForNavigation(ns.testCtx.Page(), link)
+	if err != nil {
+		return fmt.Errorf("could not navigate via link '%s': %w", linkText, err)
+	}
+
+	// Same-document navigations have no response; don't keep the previous page's
+	ns.response = nil
+	if resp != nil {
+		if ns.response, err = support.NewNavigationResult(resp); err != nil {
+			return fmt.Errorf("could not read response after clicking '%s': %w", linkText, err)
 		}
 	}
 
-	return fmt.Errorf("could not find or click navigation link '%s': %w", linkText, lastErr)
+	return nil
 }
 
 // iShouldBeOnPage checks if we're on the expected page
@@ -203,7 +182,7 @@ func (ns *NavigationSteps) iShouldBeOnPage(pageName string) error {
 	}
 
 	expectedURL := ns.testCtx.GetPageURL(pageName)
-	currentURL := ns.browser.GetURL()
+	currentURL := ns.testCtx.Page().URL()
 	if currentURL == "" {
 		return fmt.Errorf("could not get current URL")
 	}
@@ -219,3 +198,134 @@ func (ns *NavigationSteps) iShouldBeOnPage(pageName string) error {
 
 	return nil
 }
+
+// thereShouldBeNoJavaScriptErrors fails on console errors or uncaught exceptions
+func (ns *NavigationSteps) thereShouldBeNoJavaScriptErrors() error {
+	if ns.testCtx.Monitor == nil {
+		return fmt.Errorf("page monitor not initialized - navigation step should run first")
+	}
+
+	if errs := ns.testCtx.Monitor.JavaScriptErrors(); len(errs) > 0 {
+		return fmt.Errorf("found %d JavaScript errors:\n%s", len(errs), support.FormatProblems(errs))
+	}
+
+	return nil
+}
+
+// thereShouldBeNoFailedNetworkRequests fails on aborted requests or HTTP error responses
+func (ns *NavigationSteps) thereShouldBeNoFailedNetworkRequests() error {
+	if ns.testCtx.Monitor == nil {
+		return fmt.Errorf("page monitor not initialized - navigation step should run first")
+	}
+
+	if failures := ns.testCtx.Monitor.FailedRequests(); len(failures) > 0 {
+		return fmt.Errorf("found %d failed network requests:\n%s", len(failures), support.FormatProblems(failures))
+	}
+
+	return nil
+}
+
+// requireResponse returns the last captured main-document response
+func (ns *NavigationSteps) requireResponse() (*support.NavigationResult, error) {
+	if ns.response == nil {
+		return nil, fmt.Errorf("no navigation response captured - navigate to a page first")
+	}
+	return ns.response, nil
+}
+
+// theResponseStatusShouldBe checks the main-document HTTP status
+func (ns *NavigationSteps) theResponseStatusShouldBe(status int) error {
+	resp, err := ns.requireResponse()
+	if err != nil {
+		return err
+	}
+
+	if resp.Status != status {
+		return fmt.Errorf("expected HTTP %d but %s returned %d", status, resp.URL, resp.Status)
+	}
+
+	return nil
+}
+
+// theResponseContentTypeShouldBe checks the main-document media type
+func (ns *NavigationSteps) theResponseContentTypeShouldBe(mediaType string) error {
+	resp, err := ns.requireResponse()
+	if err != nil {
+		return err
+	}
+
+	if resp.MediaType() != strings.ToLower(mediaType) {
+		return fmt.Errorf("expected content-type %s but %s returned %q", mediaType, resp.URL, resp.ContentType)
+	}
+
+	return nil
+}
+
+// thePageShouldNotHaveBeenRedirected checks the navigation had an empty redirect chain
+func (ns *NavigationSteps) thePageShouldNotHaveBeenRedirected() error {
+	resp, err := ns.requireResponse()
+	if err != nil {
+		return err
+	}
+
+	if resp.Redirected() {
+		return fmt.Errorf("expected no redirects but got: %s", resp.DescribeRedirects())
+	}
+
+	return nil
+}
+
+// iShouldHaveBeenRedirectedToThePage checks the navigation redirected to the expected page
+func (ns *NavigationSteps) iShouldHaveBeenRedirectedToThePage(pageName string) error {
+	resp, err := ns.requireResponse()
+	if err != nil {
+		return err
+	}
+
+	expectedURL := ns.testCtx.GetPageURL(pageName)
+	if !resp.Redirected() {
+		return fmt.Errorf("expected a redirect to %s but %s was served directly", expectedURL, resp.URL)
+	}
+	if resp.URL != expectedURL {
+		return fmt.Errorf("expected redirect to %s but got: %s", expectedURL, resp.DescribeRedirects())
+	}
+
+	return nil
+}
+
+// iShouldSeeTheNotFoundPage checks for a 404 status and the site's 404 layout
+func (ns *NavigationSteps) iShouldSeeTheNotFoundPage() error {
+	if err := ns.theResponseStatusShouldBe(404); err != nil {
+		return err
+	}
+
+	notFound, err := support.IsNotFoundPage(ns.testCtx.Page())
+	if err != nil {
+		return err
+	}
+	if !notFound {
+		return fmt.Errorf("page did not render the not found layout (%s missing)", support.NotFoundMarker)
+	}
+
+	return nil
+}
+
+// thePageShouldHaveAHeading checks whether a heading with the exact text is rendered
+func (ns *NavigationSteps) thePageShouldHaveAHeading(should, text string) error {
+	if ns.browser == nil {
+		return fmt.Errorf("browser not initialized")
+	}
+
+	count, err := ns.testCtx.Page().GetByRole(*playwright.AriaRoleHeading, playwright.PageGetByRoleOptions{
+		Name:  text,
+		Exact: playwright.Bool(true),
+	}).Count()
+	if err != nil {
+		return fmt.Errorf("could not look for heading %q: %w", text, err)
+	}
+
+	if want := should == "should"; (count > 0) != want {
+		return fmt.Errorf("expected the page %s have a %q heading, found %d", should, text, count)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package step_definitions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"pwarnock-tests/support"
+)
+
+// KeyboardSteps implements keyboard navigation and focus order steps
+type KeyboardSteps struct {
+	testCtx   *support.TestContext
+	stops     []support.FocusStop
+	truncated bool
+}
+
+// NewKeyboardSteps creates a new KeyboardSteps instance
+func NewKeyboardSteps(ctx *support.TestContext) *KeyboardSteps {
+	return &KeyboardSteps{
+		testCtx: ctx,
+	}
+}
+
+// SetTestContext sets the test context (for delayed initialization)
+func (ks *KeyboardSteps) SetTestContext(ctx *support.TestContext) {
+	ks.testCtx = ctx
+	ks.stops = nil
+	ks.truncated = false
+}
+
+// RegisterSteps registers all keyboard steps with the scenario context
+func (ks *KeyboardSteps) RegisterSteps(ctx *godog.ScenarioContext) {
+	ctx.Step(`^I tab through the page$`, ks.iTabThroughThePage)
+	ctx.Step(`^the skip link should be the first focus stop$`, ks.theSkipLinkShouldBeTheFirstFocusStop)
+	ctx.Step(`^every focus stop should have an accessible name$`, ks.everyFocusStopShouldHaveAnAccessibleName)
+	ctx.Step(`^every focus stop should show a visible focus indicator$`, ks.everyFocusStopShouldShowAVisibleFocusIndicator)
+	ctx.Step(`^every interactive element in the (navigation|cards|carousel) should be reachable by keyboard$`, ks.everyInteractiveElementShouldBeReachableByKeyboard)
+	ctx.Step(`^I press "([^"]*)"$`, ks.iPress)
+	ctx.Step(`^I press Enter to follow the focused link$`, ks.iPressEnterToFollowTheFocusedLink)
+	ctx.Step(`^I tab to the "([^"]*)" (?:link|button)$`, ks.iTabTo)
+	ctx.Step(`^the main content should have focus$`, ks.theMainContentShouldHaveFocus)
+	ctx.Step(`^the mobile menu should be (open|closed)$`, ks.theMobileMenuShouldBe)
+}
+
+// iTabThroughThePage records the focus order from the top of the page
+func (ks *KeyboardSteps) iTabThroughThePage() error {
+	if ks.testCtx.Browser == nil {
+		return fmt.Errorf("browser not initialized - navigation step should run first")
+	}
+
+	stops, truncated, err := support.TabThrough(ks.testCtx.Page(), support.MaxFocusStops)
+	if err != nil {
+		return err
+	}
+	ks.stops = stops
+	ks.truncated = truncated
+
+	ks.testCtx.Logf("Focus order (%d stops):\n%s", len(stops), support.FormatFocusOrder(stops))
+	if truncated {
+		ks.testCtx.Logf("Stopped tabbing after %d stops", support.MaxFocusStops)
+	}
+	return nil
+}
+
+// recorded returns the focus order, failing when no step has recorded one
+func (ks *KeyboardSteps) recorded() ([]support.FocusStop, error) {
+	if ks.stops == nil {
+		return nil, fmt.Errorf("no focus order recorded - tab through the page first")
+	}
+	return ks.stops, nil
+}
+
+// theSkipLinkShouldBeTheFirstFocusStop checks that the first Tab lands on a skip link
+func (ks *KeyboardSteps) theSkipLinkShouldBeTheFirstFocusStop() error {
+	stops, err := ks.recorded()
+	if err != nil {
+		return err
+	}
+	if len(stops) == 0 {
+		return fmt.Errorf("nothing on the page receives keyboard focus")
+	}
+	if !stops[0].IsSkipLink() {
+		return fmt.Errorf("first focus stop is %s, expected a skip link", stops[0])
+	}
+	return nil
+}
+
+// everyFocusStopShouldHaveAnAccessibleName fails on stops a screen reader cannot announce
+func (ks *KeyboardSteps) everyFocusStopShouldHaveAnAccessibleName() error {
+	stops, err := ks.recorded()
+	if err != nil {
+		return err
+	}
+	if unnamed := support.UnnamedStops(stops); len(unnamed) > 0 {
+		return fmt.Errorf("%d focus stops have no accessible name:\n%s", len(unnamed), support.FormatFocusOrder(unnamed))
+	}
+	return nil
+}
+
+// everyFocusStopShouldShowAVisibleFocusIndicator fails on stops with no outline or ring while focused
+func (ks *KeyboardSteps) everyFocusStopShouldShowAVisibleFocusIndicator() error {
+	stops, err := ks.recorded()
+	if err != nil {
+		return err
+	}
+	if missing := support.StopsWithoutFocusIndicator(stops); len(missing) > 0 {
+		return fmt.Errorf("%d focus stops show no focus indicator:\n%s", len(missing), support.FormatFocusOrder(missing))
+	}
+	return nil
+}
+
+// everyInteractiveElementShouldBeReachableByKeyboard checks that tabbing
+// focused every visible control in a region
+func (ks *KeyboardSteps) everyInteractiveElementShouldBeReachableByKeyboard(region string) error {
+	if _, err := ks.recorded(); err != nil {
+		return err
+	}
+
+	unreachable, found, err := support.UnreachableElements(ks.testCtx.Page(), support.KeyboardRegions[region])
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no %s found on the page (%s)", region, support.KeyboardRegions[region])
+	}
+	if len(unreachable) > 0 {
+		msg := fmt.Sprintf("%d interactive elements in the %s are not reachable with Tab:\n  %s",
+			len(unreachable), region, strings.Join(unreachable, "\n  "))
+		if ks.truncated {
+			msg += fmt.Sprintf("\n(tabbing stopped after %d stops)", support.MaxFocusStops)
+		}
+		return fmt.Errorf("%s", msg)
+	}
+	return nil
+}
+
+// iPress presses a key on the focused element
+func (ks *KeyboardSteps) iPress(key string) error {
+	if ks.testCtx.Browser == nil {
+		return fmt.Errorf("browser not initialized - navigation step should run first")
+	}
+
+	if err := ks.testCtx.Page().Keyboard().Press(key); err != nil {
+		return fmt.Errorf("could not press %s: %w", key, err)
+	}
+	return nil
+}
+
+// iPressEnterToFollowTheFocusedLink activates the focused link and waits for the new page
+func (ks *KeyboardSteps) iPressEnterToFollowTheFocusedLink() error {
+	if ks.testCtx.Browser == nil {
+		return fmt.Errorf("browser not initialized - navigation step should run first")
+	}
+
+	resp, err := support.PressAndWaitForNavigation(ks.testCtx.Page(), "Enter")
+	if err != nil {
+		return err
+	}
+	if resp != nil && resp.Status() >= 400 {
+		return fmt.Errorf("following the focused link returned status %d", resp.Status())
+	}
+	return nil
+}
+
+// iTabTo presses Tab until the named link or button has focus
+func (ks *KeyboardSteps) iTabTo(name string) error {
+	if ks.testCtx.Browser == nil {
+		return fmt.Errorf("browser not initialized - navigation step should run first")
+	}
+
+	stop, err := support.TabTo(ks.testCtx.Page(), name, support.MaxFocusStops)
+	if err != nil {
+		return err
+	}
+	ks.testCtx.Logf("Focused %s", stop)
+	return nil
+}
+
+// theMainContentShouldHaveFocus checks that the skip link moved focus to #main-content
+func (ks *KeyboardSteps) theMainContentShouldHaveFocus() error {
+	if ks.testCtx.Browser == nil {
+		return fmt.Errorf("browser not initialized - navigation step should run first")
+	}
+
+	id, err := ks.testCtx.Page().Evaluate(`() => document.activeElement ? document.activeElement.id : ''`)
+	if err != nil {
+		return fmt.Errorf("could not read focused element: %w", err)
+	}
+	if id != "main-content" {
+		return fmt.Errorf("expected #main-content to have focus, got %q", id)
+	}
+	return nil
+}
+
+// theMobileMenuShouldBe checks whether the hamburger dropdown is showing
+func (ks *KeyboardSteps) theMobileMenuShouldBe(state string) error {
+	if ks.testCtx.Browser == nil {
+		return fmt.Errorf("browser not initialized - navigation step should run first")
+	}
+
+	open, err := support.NewNavigationMenu(ks.testCtx.Page()).IsMobileMenuOpen()
+	if err != nil {
+		return err
+	}
+	if open != (state == "open") {
+		return fmt.Errorf("expected the mobile menu to be %s", state)
+	}
+	return nil
+}
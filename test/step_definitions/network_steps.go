@@ -0,0 +1,88 @@
+package step_definitions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"pwarnock-tests/support"
+)
+
+// NetworkSteps implements assertions on the requests a page makes
+type NetworkSteps struct {
+	testCtx *support.TestContext
+}
+
+// NewNetworkSteps creates a new NetworkSteps instance
+func NewNetworkSteps(ctx *support.TestContext) *NetworkSteps {
+	return &NetworkSteps{
+		testCtx: ctx,
+	}
+}
+
+// SetTestContext sets the test context (for delayed initialization)
+func (ns *NetworkSteps) SetTestContext(ctx *support.TestContext) {
+	ns.testCtx = ctx
+}
+
+// RegisterSteps registers all network steps with the scenario context
+func (ns *NetworkSteps) RegisterSteps(ctx *godog.ScenarioContext) {
+	ctx.Step(`^the page should make no requests to third-party domains$`, ns.thePageShouldMakeNoThirdPartyRequests)
+	ctx.Step(`^the page should make no requests to third-party domains except "([^"]*)"$`, ns.thePageShouldMakeNoThirdPartyRequestsExcept)
+	ctx.Step(`^the page should request "([^"]*)"$`, ns.thePageShouldRequest)
+}
+
+// pageRequests returns the requests made by the currently loaded page
+func (ns *NetworkSteps) pageRequests() ([]support.NetworkRequest, error) {
+	if ns.testCtx.Network == nil {
+		return nil, fmt.Errorf("network routing not started - navigation step should run first")
+	}
+	return ns.testCtx.Network.RequestsForPage(ns.testCtx.Page().URL()), nil
+}
+
+// thePageShouldMakeNoThirdPartyRequests fails on any request that leaves the site
+func (ns *NetworkSteps) thePageShouldMakeNoThirdPartyRequests() error {
+	return ns.thePageShouldMakeNoThirdPartyRequestsExcept("")
+}
+
+// thePageShouldMakeNoThirdPartyRequestsExcept allows a comma-separated list of hosts
+func (ns *NetworkSteps) thePageShouldMakeNoThirdPartyRequestsExcept(hosts string) error {
+	requests, err := ns.pageRequests()
+	if err != nil {
+		return err
+	}
+
+	var allowed []string
+	for _, host := range strings.Split(hosts, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			allowed = append(allowed, host)
+		}
+	}
+
+	unexpected := support.UnexpectedThirdParty(requests, allowed)
+	if len(unexpected) > 0 {
+		return fmt.Errorf("%s made %d unexpected third-party requests (%s):\n%s",
+			ns.testCtx.Page().URL(), len(unexpected),
+			strings.Join(support.ThirdPartyHosts(unexpected), ", "),
+			support.FormatRequests(unexpected))
+	}
+
+	return nil
+}
+
+// thePageShouldRequest checks a host was contacted, e.g. by an embed
+func (ns *NetworkSteps) thePageShouldRequest(host string) error {
+	requests, err := ns.pageRequests()
+	if err != nil {
+		return err
+	}
+
+	for _, req := range requests {
+		if req.Host() == host || strings.HasSuffix(req.Host(), "."+host) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s made no requests to %s (third-party hosts: %s)",
+		ns.testCtx.Page().URL(), host, strings.Join(support.ThirdPartyHosts(requests), ", "))
+}
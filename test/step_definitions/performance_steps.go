@@ -55,9 +55,10 @@ func (ps *PerformanceSteps) iMeasurePageLoadPerformance() error {
 	ps.startTime = time.Now()
 
 	// Use simple performance measurement
-	page := ps.browser.GetPage()
+	page := ps.testCtx.Page()
 
-	// Wait for page to fully load
+	// Wait for page to fully load. Third-party embeds are stubbed by the
+	// network router, so networkidle settles without reaching the internet.
 	err := page.WaitForLoadState(playwright.PageWaitForLoadStateOptions{
 		State: playwright.LoadStateNetworkidle,
 	})
@@ -191,13 +192,15 @@ func (ps *PerformanceSteps) iSetViewportToMobileSize() error {
 	}
 	ps.browser = ps.testCtx.Browser
 
-	err := ps.browser.SetViewport(375, 667) // iPhone dimensions
+	err := ps.testCtx.Page().SetViewportSize(375, 667) // iPhone dimensions
 	if err != nil {
 		return fmt.Errorf("could not set mobile viewport: %w", err)
 	}
 
 	// Wait for responsive layout to adjust
-	time.Sleep(500 * time.Millisecond)
+	if err := support.WaitForLayoutSettled(ps.testCtx.Page()); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -227,7 +230,7 @@ func (ps *PerformanceSteps) allElementsShouldBeProperlySizedForMobileViewport()
 		return fmt.Errorf("browser not initialized")
 	}
 
-	page := ps.browser.GetPage()
+	page := ps.testCtx.Page()
 
 	// Check if horizontal scrollbar is present (indicates overflow)
 	hasHorizontalScroll, err := page.Evaluate("() => { return document.body.scrollWidth > document.body.clientWidth; }")
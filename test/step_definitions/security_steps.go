@@ -0,0 +1,246 @@
+package step_definitions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cucumber/godog"
+	"pwarnock-tests/support"
+)
+
+// SecuritySteps implements checks on the security posture of a static build
+type SecuritySteps struct {
+	testCtx *support.TestContext
+}
+
+// NewSecuritySteps creates a new SecuritySteps instance
+func NewSecuritySteps(ctx *support.TestContext) *SecuritySteps {
+	return &SecuritySteps{
+		testCtx: ctx,
+	}
+}
+
+// SetTestContext sets the test context (for delayed initialization)
+func (ss *SecuritySteps) SetTestContext(ctx *support.TestContext) {
+	ss.testCtx = ctx
+}
+
+// RegisterSteps registers all security steps with the scenario context
+func (ss *SecuritySteps) RegisterSteps(ctx *godog.ScenarioContext) {
+	ctx.Step(`^every page should have the expected security meta tags$`, ss.everyPageShouldHaveTheExpectedSecurityMetaTags)
+	ctx.Step(`^security\.txt should be well-formed$`, ss.securityTxtShouldBeWellFormed)
+	ctx.Step(`^no page should load insecure http resources$`, ss.noPageShouldLoadInsecureResources)
+	ctx.Step(`^no page should have inline scripts without a nonce$`, ss.noPageShouldHaveInlineScriptsWithoutANonce)
+	ctx.Step(`^every link opening a new tab should have rel="noopener"$`, ss.everyNewTabLinkShouldHaveNoopener)
+	ctx.Step(`^every external script and stylesheet should use SRI from an allowed CDN$`, ss.everyExternalResourceShouldUseSRIFromAnAllowedCDN)
+	ctx.Step(`^every external resource should match its integrity hash$`, ss.everyExternalResourceShouldMatchItsIntegrityHash)
+	ctx.Step(`^every allowlisted third-party embed should load asynchronously$`, ss.everyAllowlistedThirdPartyEmbedShouldLoadAsynchronously)
+}
+
+// buildDir returns the static build under inspection
+func (ss *SecuritySteps) buildDir() (string, error) {
+	if ss.testCtx.Build == nil {
+		return "", fmt.Errorf(`no site build to inspect - add 'Given the "production" site is built'`)
+	}
+	return ss.testCtx.Build.Dir, nil
+}
+
+// scan runs a check over every page of the build under inspection
+func (ss *SecuritySteps) scan(check func(document string) []string) (support.PageFindings, error) {
+	dir, err := ss.buildDir()
+	if err != nil {
+		return nil, err
+	}
+	return support.ScanSitePages(dir, check)
+}
+
+// everyPageShouldHaveTheExpectedSecurityMetaTags checks security-headers.html output
+func (ss *SecuritySteps) everyPageShouldHaveTheExpectedSecurityMetaTags() error {
+	findings, err := ss.scan(func(document string) []string {
+		if len(support.FindTags(document, "head")) == 0 {
+			// HTML fragments have no head partials to check
+			return nil
+		}
+		return support.CheckSecurityMeta(document, support.ExpectedSecurityMeta)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("%d pages are missing security meta tags:\n%s", len(findings), findings)
+	}
+	return nil
+}
+
+// securityTxtShouldBeWellFormed validates the published security.txt
+func (ss *SecuritySteps) securityTxtShouldBeWellFormed() error {
+	dir, err := ss.buildDir()
+	if err != nil {
+		return err
+	}
+
+	path, err := support.FindSecurityTxt(dir)
+	if err != nil {
+		return err
+	}
+
+	securityTxt, err := support.LoadSecurityTxt(path)
+	if err != nil {
+		return err
+	}
+
+	if problems := securityTxt.Validate(time.Now()); len(problems) > 0 {
+		return fmt.Errorf("%s is not a valid security.txt:\n  - %s", path, strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// noPageShouldLoadInsecureResources detects mixed content
+func (ss *SecuritySteps) noPageShouldLoadInsecureResources() error {
+	findings, err := ss.scan(support.FindMixedContent)
+	if err != nil {
+		return err
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("found %d insecure http resources:\n%s", findings.Count(), findings)
+	}
+	return nil
+}
+
+// noPageShouldHaveInlineScriptsWithoutANonce enforces a nonce-based CSP
+func (ss *SecuritySteps) noPageShouldHaveInlineScriptsWithoutANonce() error {
+	findings, err := ss.scan(support.InlineScriptsWithoutNonce)
+	if err != nil {
+		return err
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("found %d inline scripts without a nonce:\n%s", findings.Count(), findings)
+	}
+	return nil
+}
+
+// everyNewTabLinkShouldHaveNoopener reports target="_blank" links missing rel="noopener"
+func (ss *SecuritySteps) everyNewTabLinkShouldHaveNoopener() error {
+	findings, err := ss.scan(support.BlankTargetsWithoutNoopener)
+	if err != nil {
+		return err
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("found %d target=\"_blank\" links without rel=\"noopener\":\n%s", findings.Count(), findings)
+	}
+	return nil
+}
+
+// everyExternalResourceShouldUseSRIFromAnAllowedCDN checks integrity,
+// crossorigin and the data/cdn.toml host allowlist. Embed hosts are checked
+// by everyAllowlistedThirdPartyEmbedShouldLoadAsynchronously instead.
+func (ss *SecuritySteps) everyExternalResourceShouldUseSRIFromAnAllowedCDN() error {
+	config, err := support.LoadCDNConfig(support.CDNDataPath)
+	if err != nil {
+		return err
+	}
+	allowed := config.AllowedHosts()
+
+	findings, err := ss.scan(func(document string) []string {
+		var problems []string
+		for _, res := range support.FindExternalResources(document, ss.testCtx.Build.BaseURL) {
+			if _, ok := config.EmbedFor(res.URL); ok {
+				continue
+			}
+			for _, problem := range support.CheckExternalResource(res, allowed) {
+				problems = append(problems, fmt.Sprintf("%s: %s", res, problem))
+			}
+		}
+		return problems
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("found %d problems with external resources (allowed hosts: %s):\n%s",
+			findings.Count(), strings.Join(allowed, ", "), findings)
+	}
+	return nil
+}
+
+// everyExternalResourceShouldMatchItsIntegrityHash verifies integrity values
+// against the pinned copies in fixtures/cdn, without touching the network
+func (ss *SecuritySteps) everyExternalResourceShouldMatchItsIntegrityHash() error {
+	fetch := support.FixtureFetcher(support.CDNFixturesDir)
+	verified := make(map[string]error)
+
+	findings, err := ss.scan(func(document string) []string {
+		var problems []string
+		for _, res := range support.FindExternalResources(document, ss.testCtx.Build.BaseURL) {
+			if res.Integrity == "" {
+				continue
+			}
+			key := res.URL + " " + res.Integrity
+			verr, seen := verified[key]
+			if !seen {
+				content, err := fetch(res.URL)
+				if err == nil {
+					err = support.VerifyIntegrity(res.Integrity, content)
+				}
+				verified[key] = err
+				verr = err
+			}
+			if verr != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", res, verr))
+			}
+		}
+		return problems
+	})
+	if err != nil {
+		return err
+	}
+
+	ss.testCtx.Logf("Verified %d external resource integrity values", len(verified))
+	if len(findings) > 0 {
+		return fmt.Errorf("found %d integrity failures:\n%s", findings.Count(), findings)
+	}
+	return nil
+}
+
+// everyAllowlistedThirdPartyEmbedShouldLoadAsynchronously checks the scripts on
+// the [[embeds]] hosts of data/cdn.toml, which cannot be pinned by hash and
+// so are exempt from SRI, and logs each embed with its reason
+func (ss *SecuritySteps) everyAllowlistedThirdPartyEmbedShouldLoadAsynchronously() error {
+	config, err := support.LoadCDNConfig(support.CDNDataPath)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	findings, err := ss.scan(func(document string) []string {
+		var problems []string
+		for _, res := range support.FindExternalResources(document, ss.testCtx.Build.BaseURL) {
+			embed, ok := config.EmbedFor(res.URL)
+			if !ok {
+				continue
+			}
+			if !seen[res.URL] {
+				seen[res.URL] = true
+				ss.testCtx.Logf("Embed %s allowed without SRI: %s", res.URL, embed.Reason)
+			}
+			for _, problem := range support.CheckEmbedResource(res) {
+				problems = append(problems, fmt.Sprintf("%s: %s", res, problem))
+			}
+		}
+		return problems
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("found %d problems with third-party embeds:\n%s", findings.Count(), findings)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package step_definitions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"pwarnock-tests/support"
+)
+
+// ThemeSteps implements DaisyUI theme switching steps driven by data/themes.yaml
+type ThemeSteps struct {
+	testCtx *support.TestContext
+	themes  []support.Theme
+}
+
+// NewThemeSteps creates a new ThemeSteps instance
+func NewThemeSteps(ctx *support.TestContext) *ThemeSteps {
+	return &ThemeSteps{
+		testCtx: ctx,
+	}
+}
+
+// SetTestContext sets the test context (for delayed initialization)
+func (ts *ThemeSteps) SetTestContext(ctx *support.TestContext) {
+	ts.testCtx = ctx
+}
+
+// RegisterSteps registers all theme steps with the scenario context
+func (ts *ThemeSteps) RegisterSteps(ctx *godog.ScenarioContext) {
+	ctx.Step(`^the "([^"]*)" theme is active$`, ts.theThemeIsActive)
+	ctx.Step(`^I switch to the "([^"]*)" theme$`, ts.theThemeIsActive)
+	ctx.Step(`^the active theme should be "([^"]*)"$`, ts.theActiveThemeShouldBe)
+	ctx.Step(`^the page should pass color contrast checks$`, ts.thePageShouldPassColorContrastChecks)
+	ctx.Step(`^every accessible theme should pass color contrast checks$`, ts.everyAccessibleThemeShouldPassColorContrastChecks)
+}
+
+// loadThemes reads data/themes.yaml once per step instance
+func (ts *ThemeSteps) loadThemes() ([]support.Theme, error) {
+	if ts.themes == nil {
+		themes, err := support.LoadThemes(support.ThemesDataPath)
+		if err != nil {
+			return nil, err
+		}
+		ts.themes = themes
+	}
+	return ts.themes, nil
+}
+
+// theThemeIsActive applies a theme from the catalogue to the current page
+func (ts *ThemeSteps) theThemeIsActive(id string) error {
+	if ts.testCtx.Browser == nil {
+		return fmt.Errorf("browser not initialized - navigation step should run first")
+	}
+
+	themes, err := ts.loadThemes()
+	if err != nil {
+		return err
+	}
+	if _, ok := support.FindTheme(themes, id); !ok {
+		return fmt.Errorf("theme %q is not defined in %s", id, support.ThemesDataPath)
+	}
+
+	if err := support.ApplyTheme(ts.testCtx.Page(), id); err != nil {
+		return err
+	}
+
+	ts.testCtx.Logf("Applied theme %s", id)
+	return nil
+}
+
+// theActiveThemeShouldBe checks the page's data-theme
+func (ts *ThemeSteps) theActiveThemeShouldBe(id string) error {
+	if ts.testCtx.Browser == nil {
+		return fmt.Errorf("browser not initialized - navigation step should run first")
+	}
+
+	current, err := support.CurrentTheme(ts.testCtx.Page())
+	if err != nil {
+		return err
+	}
+	if current != id {
+		return fmt.Errorf("expected theme %q but page has data-theme %q", id, current)
+	}
+
+	return nil
+}
+
+// thePageShouldPassColorContrastChecks runs the axe color-contrast rule in the current theme
+func (ts *ThemeSteps) thePageShouldPassColorContrastChecks() error {
+	if ts.testCtx.Browser == nil {
+		return fmt.Errorf("browser not initialized - navigation step should run first")
+	}
+
+	violations, err := support.RunAxeRules(ts.testCtx.Page(), []string{"color-contrast"})
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		theme, _ := support.CurrentTheme(ts.testCtx.Page())
+		return fmt.Errorf("theme %q fails color contrast:%s", theme, support.SummarizeViolationsWithSources(ts.testCtx.Page().URL(), violations))
+	}
+
+	return nil
+}
+
+// everyAccessibleThemeShouldPassColorContrastChecks applies each accessible theme in turn
+// and reports every theme that fails, not just the first
+func (ts *ThemeSteps) everyAccessibleThemeShouldPassColorContrastChecks() error {
+	if ts.testCtx.Browser == nil {
+		return fmt.Errorf("browser not initialized - navigation step should run first")
+	}
+
+	themes, err := ts.loadThemes()
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, theme := range support.AccessibleThemes(themes) {
+		if err := ts.theThemeIsActive(theme.ID); err != nil {
+			return err
+		}
+		if err := ts.thePageShouldPassColorContrastChecks(); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d accessible themes fail color contrast on %s:\n%s",
+			len(failures), ts.testCtx.Page().URL(), strings.Join(failures, "\n"))
+	}
+
+	return nil
+}
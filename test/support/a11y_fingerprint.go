@@ -0,0 +1,122 @@
+package support
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pwarnock/go-playwright-testkit/pkg/scanner"
+)
+
+// A11yIssueLabel marks issues filed by the accessibility scanner
+const A11yIssueLabel = "a11y"
+
+var (
+	// fingerprintPattern finds the fingerprint line in an issue description
+	fingerprintPattern = regexp.MustCompile(`(?m)^Fingerprint: (a11y-[0-9a-f]{12})\s*$`)
+	// templatePattern finds the page template line in an issue description
+	templatePattern = regexp.MustCompile(`(?m)^Template: (\S+)\s*$`)
+	// nthPattern matches positional pseudo-classes whose index changes with content
+	nthPattern = regexp.MustCompile(`:(nth-child|nth-of-type|nth-last-child|nth-last-of-type)\(\s*\d+\s*\)`)
+	// generatedNamePattern matches digit runs inside #ids and .classes, e.g. #post-123
+	generatedNamePattern = regexp.MustCompile(`([#.][A-Za-z_-][\w-]*?)\d+`)
+	// whitespacePattern collapses runs of whitespace and combinator spacing
+	whitespacePattern = regexp.MustCompile(`\s*([>+~])\s*|\s+`)
+)
+
+// NormalizeSelector strips the parts of an axe target that vary between pages
+// rendered by the same template: positional indices and numbered ids/classes
+func NormalizeSelector(selector string) string {
+	s := strings.TrimSpace(selector)
+	s = nthPattern.ReplaceAllString(s, ":$1(n)")
+	s = generatedNamePattern.ReplaceAllString(s, "${1}N")
+	s = whitespacePattern.ReplaceAllStringFunc(s, func(m string) string {
+		if c := strings.TrimSpace(m); c != "" {
+			return " " + c + " "
+		}
+		return " "
+	})
+	return s
+}
+
+// PageTemplate classifies a page URL by section and kind, e.g. "/" is
+// "home", "/tools/" is "tools/list" and "/tools/bun/" is "tools/single"
+func PageTemplate(rawURL string) string {
+	p := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		p = u.Path
+	}
+
+	segments := strings.FieldsFunc(strings.ToLower(p), func(r rune) bool { return r == '/' })
+	switch len(segments) {
+	case 0:
+		return "home"
+	case 1:
+		if strings.Contains(segments[0], ".") {
+			// Top-level files such as /404.html
+			return strings.TrimSuffix(segments[0], ".html")
+		}
+		return segments[0] + "/list"
+	default:
+		return segments[0] + "/single"
+	}
+}
+
+// A11yFingerprint identifies a violation independent of the page it was seen
+// on: the axe rule, the normalized selector and the page template. Runs are
+// keyed on it so a diff shows which element regressed.
+func A11yFingerprint(ruleID, selector, pageURL string) string {
+	sum := sha256.Sum256([]byte(ruleID + "\n" + NormalizeSelector(selector) + "\n" + PageTemplate(pageURL)))
+	return "a11y-" + hex.EncodeToString(sum[:])[:12]
+}
+
+// A11yIssueFingerprint identifies the issue a violation is filed under: the
+// axe rule and the page template, whatever elements fail it
+func A11yIssueFingerprint(ruleID, template string) string {
+	sum := sha256.Sum256([]byte(ruleID + "\n" + template))
+	return "a11y-" + hex.EncodeToString(sum[:])[:12]
+}
+
+// IssueFingerprint returns the fingerprint of the issue a scanner finding is filed under
+func IssueFingerprint(issue scanner.AccessibilityIssue) string {
+	return A11yIssueFingerprint(issue.ID, PageTemplate(issue.URL))
+}
+
+// FingerprintOf returns the fingerprint embedded in a tracker issue, or ""
+func FingerprintOf(issue Issue) string {
+	if m := fingerprintPattern.FindStringSubmatch(issue.Description); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// TemplateOf returns the page template an accessibility issue was filed for, or ""
+func TemplateOf(issue Issue) string {
+	if m := templatePattern.FindStringSubmatch(issue.Description); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// WithFingerprint appends the fingerprint line to a description
+func WithFingerprint(description, fingerprint string) string {
+	return strings.TrimRight(description, "\n") + "\n\nFingerprint: " + fingerprint
+}
+
+// FindByFingerprint returns the most recently updated issue carrying fingerprint
+func FindByFingerprint(issues []Issue, fingerprint string) *Issue {
+	var found *Issue
+	for i := range issues {
+		if FingerprintOf(issues[i]) != fingerprint {
+			continue
+		}
+		// Prefer an open issue, then the latest one
+		if found == nil || (issues[i].IsOpen() && !found.IsOpen()) ||
+			(issues[i].IsOpen() == found.IsOpen() && issues[i].UpdatedAt.After(found.UpdatedAt)) {
+			found = &issues[i]
+		}
+	}
+	return found
+}
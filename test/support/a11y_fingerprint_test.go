@@ -0,0 +1,58 @@
+package support
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalizeSelector tests positional and generated parts are stripped
+func TestNormalizeSelector(t *testing.T) {
+	tests := map[string]string{
+		"article:nth-child(3) > img":        "article:nth-child(n) > img",
+		"  li:nth-of-type( 12 )>a ":         "li:nth-of-type(n) > a",
+		"#post-123 .card-4 h2":              "#post-N .card-N h2",
+		".carousel  .slide:nth-child(2)+ a": ".carousel .slide:nth-child(n) + a",
+		"button[aria-label='Close']":        "button[aria-label='Close']",
+	}
+	for selector, want := range tests {
+		assert.Equal(t, want, NormalizeSelector(selector), selector)
+	}
+}
+
+// TestPageTemplate tests URLs are grouped by section and page kind
+func TestPageTemplate(t *testing.T) {
+	assert.Equal(t, "home", PageTemplate("http://localhost:1313/"))
+	assert.Equal(t, "tools/list", PageTemplate("http://localhost:1313/tools/"))
+	assert.Equal(t, "tools/single", PageTemplate("http://localhost:1313/tools/bun/"))
+	assert.Equal(t, "blog/single", PageTemplate("/blog/posts/first-post/"))
+	assert.Equal(t, "404", PageTemplate("/404.html"))
+}
+
+// TestA11yFingerprint tests pages sharing a template share a fingerprint
+func TestA11yFingerprint(t *testing.T) {
+	bun := A11yFingerprint("image-alt", "article:nth-child(2) > img", "http://localhost:1313/tools/bun/")
+	docker := A11yFingerprint("image-alt", "article:nth-child(7) > img", "https://peterwarnock.com/tools/docker/")
+
+	assert.Regexp(t, `^a11y-[0-9a-f]{12}$`, bun)
+	assert.Equal(t, bun, docker)
+	assert.NotEqual(t, bun, A11yFingerprint("color-contrast", "article:nth-child(2) > img", "/tools/bun/"))
+	assert.NotEqual(t, bun, A11yFingerprint("image-alt", "article:nth-child(2) > img", "/blog/posts/first-post/"))
+}
+
+// TestFindByFingerprint tests the description marker round-trips and open issues win
+func TestFindByFingerprint(t *testing.T) {
+	fp := A11yFingerprint("image-alt", "img", "/")
+	description := WithFingerprint("## Accessibility Issue Found\n", fp)
+	assert.Equal(t, fp, FingerprintOf(Issue{Description: description}))
+
+	now := time.Now()
+	issues := []Issue{
+		{ID: "pw-1", Description: description, Status: IssueClosed, UpdatedAt: now},
+		{ID: "pw-2", Description: description, Status: IssueOpen, UpdatedAt: now.Add(-time.Hour)},
+		{ID: "pw-3", Description: "no fingerprint", Status: IssueOpen},
+	}
+	assert.Equal(t, "pw-2", FindByFingerprint(issues, fp).ID)
+	assert.Nil(t, FindByFingerprint(issues, "a11y-000000000000"))
+}
@@ -0,0 +1,375 @@
+package support
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pwarnock/go-playwright-testkit/pkg/scanner"
+)
+
+// sampleHTMLLimit caps the HTML snippet shown per page in an issue
+const sampleHTMLLimit = 120
+
+// A11yOccurrence is one element failing one axe rule on one page
+type A11yOccurrence struct {
+	RuleID   string
+	Title    string
+	Impact   string
+	Tags     []string
+	HelpURL  string
+	Selector string
+	URL      string
+	HTML     string
+}
+
+// Fingerprint identifies the group the occurrence belongs to
+func (o A11yOccurrence) Fingerprint() string {
+	return A11yFingerprint(o.RuleID, o.Selector, o.URL)
+}
+
+// OccurrencesFromViolations flattens axe violations on a page into one
+// occurrence per failing node
+func OccurrencesFromViolations(pageURL string, violations []AxeViolation) []A11yOccurrence {
+	var occurrences []A11yOccurrence
+	for _, v := range violations {
+		for _, node := range v.Nodes {
+			occurrences = append(occurrences, A11yOccurrence{
+				RuleID:   v.ID,
+				Title:    v.Help,
+				Impact:   v.Impact,
+				Tags:     v.Tags,
+				HelpURL:  v.HelpURL,
+				Selector: strings.Join(node.Target, " "),
+				URL:      pageURL,
+				HTML:     node.HTML,
+			})
+		}
+	}
+	return occurrences
+}
+
+// OccurrenceFromIssue converts a library scanner finding
+func OccurrenceFromIssue(issue scanner.AccessibilityIssue) A11yOccurrence {
+	return A11yOccurrence{
+		RuleID:   issue.ID,
+		Title:    issue.Title,
+		Impact:   issue.Impact,
+		Tags:     issue.Tags,
+		Selector: issue.Selector,
+		URL:      issue.URL,
+	}
+}
+
+// A11yPage is one affected page in a group. In issue groups each failing
+// selector on the page is a separate row.
+type A11yPage struct {
+	URL        string `json:"url"`
+	Selector   string `json:"selector,omitempty"` // normalized, in issue groups
+	Nodes      int    `json:"nodes"`
+	SampleHTML string `json:"sample_html,omitempty"`
+}
+
+// A11yGroup is every occurrence of one rule on one selector across pages
+// rendered by the same template, as saved in runs. IssueGroups merges the
+// selectors of a rule and template into the group filed as a single issue.
+type A11yGroup struct {
+	Fingerprint string           `json:"fingerprint"`
+	RuleID      string           `json:"rule"`
+	Title       string           `json:"title"`
+	Impact      string           `json:"impact"`
+	Tags        []string         `json:"tags,omitempty"`
+	HelpURL     string           `json:"help_url,omitempty"`
+	Template    string           `json:"template"`
+	Selector    string           `json:"selector,omitempty"`  // normalized, in run groups
+	Selectors   []string         `json:"selectors,omitempty"` // normalized, in issue groups
+	Pages       []A11yPage       `json:"pages"`
+	Sources     []SourceLocation `json:"sources,omitempty"` // likely templates and content, when known
+}
+
+// GroupOccurrences groups occurrences by their selector-level fingerprint,
+// counting nodes per page. Groups and pages are sorted for stable output.
+func GroupOccurrences(occurrences []A11yOccurrence) []A11yGroup {
+	groups := make(map[string]*A11yGroup)
+	var order []string
+
+	for _, o := range occurrences {
+		fp := o.Fingerprint()
+		group, ok := groups[fp]
+		if !ok {
+			group = &A11yGroup{
+				Fingerprint: fp,
+				RuleID:      o.RuleID,
+				Title:       o.Title,
+				Impact:      o.Impact,
+				Tags:        o.Tags,
+				HelpURL:     o.HelpURL,
+				Template:    PageTemplate(o.URL),
+				Selector:    NormalizeSelector(o.Selector),
+			}
+			groups[fp] = group
+			order = append(order, fp)
+		}
+		group.addNode(o.URL, o.HTML)
+	}
+
+	result := make([]A11yGroup, 0, len(order))
+	for _, fp := range order {
+		group := groups[fp]
+		sort.Slice(group.Pages, func(i, j int) bool { return group.Pages[i].URL < group.Pages[j].URL })
+		result = append(result, *group)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].RuleID != result[j].RuleID {
+			return result[i].RuleID < result[j].RuleID
+		}
+		return result[i].Template < result[j].Template
+	})
+	return result
+}
+
+// IssueGroups merges run groups failing the same rule on the same template
+// into one group per issue, keyed on A11yIssueFingerprint. Each page lists
+// the selectors failing on it.
+func IssueGroups(groups []A11yGroup) []A11yGroup {
+	merged := make(map[string]*A11yGroup)
+	var order []string
+
+	for _, g := range groups {
+		fp := A11yIssueFingerprint(g.RuleID, g.Template)
+		issue, ok := merged[fp]
+		if !ok {
+			issue = &A11yGroup{
+				Fingerprint: fp,
+				RuleID:      g.RuleID,
+				Title:       g.Title,
+				Impact:      g.Impact,
+				Tags:        g.Tags,
+				HelpURL:     g.HelpURL,
+				Template:    g.Template,
+			}
+			merged[fp] = issue
+			order = append(order, fp)
+		}
+		if !containsString(issue.Selectors, g.Selector) {
+			issue.Selectors = append(issue.Selectors, g.Selector)
+		}
+		for _, page := range g.Pages {
+			page.Selector = g.Selector
+			issue.Pages = append(issue.Pages, page)
+		}
+		for _, source := range g.Sources {
+			if !containsSource(issue.Sources, source) {
+				issue.Sources = append(issue.Sources, source)
+			}
+		}
+	}
+
+	result := make([]A11yGroup, 0, len(order))
+	for _, fp := range order {
+		issue := merged[fp]
+		sort.Strings(issue.Selectors)
+		sortPages(issue.Pages)
+		result = append(result, *issue)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].RuleID != result[j].RuleID {
+			return result[i].RuleID < result[j].RuleID
+		}
+		return result[i].Template < result[j].Template
+	})
+	return result
+}
+
+// containsSource reports whether sources already lists source's path
+func containsSource(sources []SourceLocation, source SourceLocation) bool {
+	for _, s := range sources {
+		if s.Path == source.Path {
+			return true
+		}
+	}
+	return false
+}
+
+// sortPages orders pages by URL, then selector
+func sortPages(pages []A11yPage) {
+	sort.Slice(pages, func(i, j int) bool {
+		if pages[i].URL != pages[j].URL {
+			return pages[i].URL < pages[j].URL
+		}
+		return pages[i].Selector < pages[j].Selector
+	})
+}
+
+// PageCount returns the number of distinct pages affected
+func (g A11yGroup) PageCount() int {
+	return countURLs(g.Pages)
+}
+
+// countURLs returns the number of distinct URLs in pages
+func countURLs(pages []A11yPage) int {
+	urls := make(map[string]bool, len(pages))
+	for _, page := range pages {
+		urls[page.URL] = true
+	}
+	return len(urls)
+}
+
+// selectors returns the normalized selectors the group covers
+func (g A11yGroup) selectors() []string {
+	if len(g.Selectors) > 0 {
+		return g.Selectors
+	}
+	return []string{g.Selector}
+}
+
+// addNode counts a failing node on a page, keeping the first HTML sample
+func (g *A11yGroup) addNode(pageURL, html string) {
+	for i := range g.Pages {
+		if g.Pages[i].URL == pageURL {
+			g.Pages[i].Nodes++
+			if g.Pages[i].SampleHTML == "" {
+				g.Pages[i].SampleHTML = html
+			}
+			return
+		}
+	}
+	g.Pages = append(g.Pages, A11yPage{URL: pageURL, Nodes: 1, SampleHTML: html})
+}
+
+// IssueTitle names the rule and the template it fails on
+func (g A11yGroup) IssueTitle() string {
+	return fmt.Sprintf("Accessibility Issue: %s (%s)", g.Title, g.Template)
+}
+
+// Description renders the issue body: rule details, the affected pages table,
+// the likely sources and the fingerprint line used for dedup
+func (g A11yGroup) Description() string {
+	var b strings.Builder
+	b.WriteString("## Accessibility Issue Found\n\n")
+	fmt.Fprintf(&b, "Rule: %s\nImpact: %s\nTemplate: %s\n", g.RuleID, g.Impact, g.Template)
+	if g.HelpURL != "" {
+		fmt.Fprintf(&b, "Help: %s\n", g.HelpURL)
+	}
+	fmt.Fprintf(&b, "Tags: %s\n\n", strings.Join(g.Tags, ", "))
+
+	b.WriteString(renderPagesSection(g.Pages))
+	if len(g.Sources) > 0 {
+		b.WriteString("\n### Likely sources\n\n")
+		for _, source := range g.Sources {
+			fmt.Fprintf(&b, "- `%s` (%s)\n", source.Path, source.Reason)
+		}
+	}
+	b.WriteString("\nThis issue was automatically created by the Go BDD accessibility scanner.")
+	return WithFingerprint(b.String(), g.Fingerprint)
+}
+
+// markdownCode renders s as inline code in a table cell
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + strings.NewReplacer("|", `\|`, "`", "'").Replace(s) + "`"
+}
+
+// tableRow renders the page as a Markdown table row
+func (p A11yPage) tableRow() string {
+	sample := strings.Join(strings.Fields(p.SampleHTML), " ")
+	if runes := []rune(sample); len(runes) > sampleHTMLLimit {
+		sample = string(runes[:sampleHTMLLimit]) + "…"
+	}
+	return fmt.Sprintf("| %s | %s | %d | %s |", p.URL, markdownCode(p.Selector), p.Nodes, markdownCode(sample))
+}
+
+// key identifies the page's row in the affected pages table
+func (p A11yPage) key() string {
+	return p.URL + "\n" + p.Selector
+}
+
+// renderPagesSection renders the affected pages heading and table
+func renderPagesSection(pages []A11yPage) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### Affected pages (%d)\n\n", countURLs(pages))
+	b.WriteString("| URL | Selector | Nodes | Sample HTML |\n| --- | --- | ---: | --- |\n")
+	for _, page := range pages {
+		b.WriteString(page.tableRow() + "\n")
+	}
+	return b.String()
+}
+
+// pagesSectionPattern matches the affected pages heading and table
+var pagesSectionPattern = regexp.MustCompile(`(?m)^### Affected pages \(\d+\)\n\n(?:\|.*\n?)*`)
+
+// pageRowPattern matches a row of the affected pages table. Issues filed
+// before selectors were listed have no selector column.
+var pageRowPattern = regexp.MustCompile("(?m)^\\| (\\S+) \\| (?:(?:`([^`]*)`)? \\| )?(\\d+) \\| (.*) \\|$")
+
+// unescapeCell reverses markdownCode
+func unescapeCell(cell string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(strings.TrimPrefix(cell, "`"), "`"), `\|`, "|")
+}
+
+// PagesInDescription returns the affected pages listed in an issue description
+func PagesInDescription(description string) []A11yPage {
+	var pages []A11yPage
+	for _, m := range pageRowPattern.FindAllStringSubmatch(description, -1) {
+		var nodes int
+		fmt.Sscanf(m[3], "%d", &nodes)
+		pages = append(pages, A11yPage{
+			URL:        m[1],
+			Selector:   strings.ReplaceAll(m[2], `\|`, "|"),
+			Nodes:      nodes,
+			SampleHTML: unescapeCell(m[4]),
+		})
+	}
+	return pages
+}
+
+// mergePages adds rows from existing that current lacks and reports
+// whether current has rows existing lacked
+func mergePages(current, existing []A11yPage) ([]A11yPage, bool) {
+	listed := make(map[string]bool, len(existing))
+	for _, page := range existing {
+		listed[page.key()] = true
+	}
+	added := false
+	seen := make(map[string]bool, len(current))
+	merged := append([]A11yPage(nil), current...)
+	for _, page := range current {
+		seen[page.key()] = true
+		if !listed[page.key()] {
+			added = true
+		}
+	}
+	for _, page := range existing {
+		// Rows without a selector predate the selector column; the scan replaces them
+		if !seen[page.key()] && (page.Selector != "" || !seenURL(current, page.URL)) {
+			merged = append(merged, page)
+		}
+	}
+	sortPages(merged)
+	return merged, added
+}
+
+// seenURL reports whether pages has a row for pageURL
+func seenURL(pages []A11yPage, pageURL string) bool {
+	for _, page := range pages {
+		if page.URL == pageURL {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeAffectedPages keeps the pages listed in an existing issue's description
+// that a new description lacks, since a scan may not visit every page. It
+// reports whether the new description lists pages the existing one did not.
+func MergeAffectedPages(description, existing string) (string, bool) {
+	current := PagesInDescription(description)
+	merged, added := mergePages(current, PagesInDescription(existing))
+	if len(merged) == len(current) {
+		return description, added
+	}
+	return pagesSectionPattern.ReplaceAllLiteralString(description, renderPagesSection(merged)), added
+}
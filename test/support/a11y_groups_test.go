@@ -0,0 +1,140 @@
+package support
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cardImageViolation is an image-alt failure in the tool card template
+func cardImageViolation(nodes ...string) AxeViolation {
+	v := AxeViolation{
+		ID:      "image-alt",
+		Impact:  "critical",
+		Help:    "Images must have alternate text",
+		HelpURL: "https://dequeuniversity.com/rules/axe/4.8/image-alt",
+		Tags:    []string{"wcag2a"},
+	}
+	for _, n := range nodes {
+		v.Nodes = append(v.Nodes, AxeNode{Target: []string{n}, HTML: `<img src="/img/logo.png" class="card | logo">`})
+	}
+	return v
+}
+
+// TestGroupOccurrences tests one run group per rule, selector and template with per-page node counts
+func TestGroupOccurrences(t *testing.T) {
+	var occurrences []A11yOccurrence
+	occurrences = append(occurrences, OccurrencesFromViolations("/tools/bun/", []AxeViolation{cardImageViolation(".card:nth-child(1) > img", ".card:nth-child(2) > img")})...)
+	occurrences = append(occurrences, OccurrencesFromViolations("/tools/docker/", []AxeViolation{cardImageViolation(".card:nth-child(4) > img")})...)
+	occurrences = append(occurrences, OccurrencesFromViolations("/blog/", []AxeViolation{cardImageViolation(".card:nth-child(1) > img")})...)
+
+	groups := GroupOccurrences(occurrences)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "blog/list", groups[0].Template)
+
+	tools := groups[1]
+	assert.Equal(t, "tools/single", tools.Template)
+	assert.Equal(t, ".card:nth-child(n) > img", tools.Selector)
+	assert.Equal(t, A11yFingerprint("image-alt", ".card:nth-child(1) > img", "/tools/bun/"), tools.Fingerprint)
+	assert.Equal(t, []A11yPage{
+		{URL: "/tools/bun/", Nodes: 2, SampleHTML: `<img src="/img/logo.png" class="card | logo">`},
+		{URL: "/tools/docker/", Nodes: 1, SampleHTML: `<img src="/img/logo.png" class="card | logo">`},
+	}, tools.Pages)
+}
+
+// TestIssueGroups tests selectors failing a rule on one template are filed as one issue listing them
+func TestIssueGroups(t *testing.T) {
+	var occurrences []A11yOccurrence
+	occurrences = append(occurrences, OccurrencesFromViolations("/tools/bun/", []AxeViolation{cardImageViolation(".card:nth-child(1) > img", ".card:nth-child(2) > img", "header > img")})...)
+	occurrences = append(occurrences, OccurrencesFromViolations("/tools/docker/", []AxeViolation{cardImageViolation(".card:nth-child(4) > img")})...)
+	occurrences = append(occurrences, OccurrencesFromViolations("/blog/", []AxeViolation{cardImageViolation(".card:nth-child(1) > img")})...)
+
+	runGroups := GroupOccurrences(occurrences)
+	require.Len(t, runGroups, 3, "runs keep one group per selector")
+
+	groups := IssueGroups(runGroups)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "blog/list", groups[0].Template)
+
+	tools := groups[1]
+	assert.Equal(t, A11yIssueFingerprint("image-alt", "tools/single"), tools.Fingerprint)
+	assert.Equal(t, []string{".card:nth-child(n) > img", "header > img"}, tools.Selectors)
+	assert.Equal(t, []A11yPage{
+		{URL: "/tools/bun/", Selector: ".card:nth-child(n) > img", Nodes: 2, SampleHTML: `<img src="/img/logo.png" class="card | logo">`},
+		{URL: "/tools/bun/", Selector: "header > img", Nodes: 1, SampleHTML: `<img src="/img/logo.png" class="card | logo">`},
+		{URL: "/tools/docker/", Selector: ".card:nth-child(n) > img", Nodes: 1, SampleHTML: `<img src="/img/logo.png" class="card | logo">`},
+	}, tools.Pages)
+	assert.Equal(t, 2, tools.PageCount())
+	assert.Equal(t, "Accessibility Issue: Images must have alternate text (tools/single)", tools.IssueTitle())
+
+	description := tools.Description()
+	assert.Contains(t, description, "Help: https://dequeuniversity.com/rules/axe/4.8/image-alt")
+	assert.Contains(t, description, "### Affected pages (2)")
+	assert.Contains(t, description, "| /tools/bun/ | `header > img` | 1 | `<img src=\"/img/logo.png\" class=\"card \\| logo\">` |")
+	assert.Equal(t, tools.Fingerprint, FingerprintOf(Issue{Description: description}))
+	assert.Equal(t, tools.Pages, PagesInDescription(description), "the table round-trips")
+}
+
+// TestPagesInDescription_Legacy tests tables filed before the selector column still parse
+func TestPagesInDescription_Legacy(t *testing.T) {
+	legacy := "### Affected pages (1)\n\n| URL | Nodes | Sample HTML |\n| --- | ---: | --- |\n| /tools/bun/ | 2 | `<img>` |\n"
+	assert.Equal(t, []A11yPage{{URL: "/tools/bun/", Nodes: 2, SampleHTML: "<img>"}}, PagesInDescription(legacy))
+
+	// The scan's rows for the page replace the legacy row
+	current := []A11yPage{{URL: "/tools/bun/", Selector: "img", Nodes: 2}}
+	merged, _ := mergePages(current, PagesInDescription(legacy))
+	assert.Equal(t, current, merged)
+}
+
+// TestMergePages tests pages from earlier scans are kept and new ones reported
+func TestMergePages(t *testing.T) {
+	group := IssueGroups(GroupOccurrences(OccurrencesFromViolations("/tools/bun/", []AxeViolation{cardImageViolation("img")})))[0]
+	existing := []A11yPage{{URL: "/tools/astro/", Selector: "img", Nodes: 3}, {URL: "/tools/bun/", Selector: "img", Nodes: 1}}
+
+	merged, added := mergePages(group.Pages, existing)
+	assert.False(t, added, "no page the issue did not already list")
+	assert.Equal(t, []string{"/tools/astro/", "/tools/bun/"}, pageURLs(merged))
+
+	group = IssueGroups(GroupOccurrences(OccurrencesFromViolations("/tools/hugo/", []AxeViolation{cardImageViolation("img")})))[0]
+	merged, added = mergePages(group.Pages, existing)
+	assert.True(t, added)
+	assert.Len(t, merged, 3)
+}
+
+// pageURLs returns the URLs of pages, for assertions
+func pageURLs(pages []A11yPage) []string {
+	urls := make([]string, 0, len(pages))
+	for _, page := range pages {
+		urls = append(urls, page.URL)
+	}
+	return urls
+}
+
+// TestAccessibilityScanner_CreateBdIssuesForAll_Grouped tests a template bug files one issue
+func TestAccessibilityScanner_CreateBdIssuesForAll_Grouped(t *testing.T) {
+	t.Setenv("CI", "")
+	tracker := NewMemoryTracker()
+	s := NewAccessibilityScanner("http://localhost:1313", tracker)
+
+	for _, page := range []string{"/tools/bun/", "/tools/docker/", "/tools/hugo/"} {
+		s.AddViolations(page, []AxeViolation{cardImageViolation("img"), {ID: "region", Impact: "moderate", Nodes: []AxeNode{{Target: []string{"main"}}}}})
+	}
+	require.NoError(t, s.CreateBdIssuesForAll())
+
+	issues, err := tracker.List(IssueFilter{})
+	require.NoError(t, err)
+	require.Len(t, issues, 1, "moderate violations are not filed")
+	assert.Equal(t, 3, strings.Count(issues[0].Description, "| /tools/"))
+
+	// A later scan of another page updates the same issue
+	s = NewAccessibilityScanner("http://localhost:1313", tracker)
+	s.AddViolations("/tools/astro/", []AxeViolation{cardImageViolation("img")})
+	require.NoError(t, s.CreateBdIssuesForAll())
+
+	issues, err = tracker.List(IssueFilter{})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Len(t, PagesInDescription(issues[0].Description), 4)
+}
@@ -0,0 +1,278 @@
+package support
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// A11yPolicyPath maps axe impact and WCAG level to tracker fields
+const A11yPolicyPath = "a11y-policy.toml"
+
+// What happens to a group of violations
+const (
+	A11yFile   = "file"   // file or update a tracker issue
+	A11yWarn   = "warn"   // print a warning only
+	A11yIgnore = "ignore" // drop silently
+)
+
+// axeImpacts are the impact levels axe reports, most severe first
+var axeImpacts = []string{"critical", "serious", "moderate", "minor"}
+
+// A11yIssueFields are the tracker fields for a violation. Unset fields keep
+// the value from the layer below; labels accumulate.
+type A11yIssueFields struct {
+	Priority *int     `toml:"priority"`
+	Type     string   `toml:"type"`
+	Labels   []string `toml:"labels"`
+}
+
+// validate checks the priority is one bd accepts
+func (f A11yIssueFields) validate() error {
+	if f.Priority != nil && (*f.Priority < 0 || *f.Priority > 4) {
+		return fmt.Errorf("priority %d is not between 0 and 4", *f.Priority)
+	}
+	return nil
+}
+
+// A11yRuleOverride is one [[override]] entry, applied after impact and WCAG level
+type A11yRuleOverride struct {
+	Rule     string `toml:"rule"`     // axe rule ID, may use path.Match wildcards
+	Template string `toml:"template"` // page template, e.g. "components/single"
+	Pages    string `toml:"pages"`    // URL path pattern every affected page must match
+	Action   string `toml:"action"`   // file, warn or ignore; empty keeps the impact's
+	Reason   string `toml:"reason"`
+	A11yIssueFields
+}
+
+// Matches reports whether the override covers a group
+func (o A11yRuleOverride) Matches(g A11yGroup) bool {
+	if o.Rule != "" {
+		if ok, _ := path.Match(o.Rule, g.RuleID); !ok {
+			return false
+		}
+	}
+	if o.Template != "" {
+		if ok, _ := path.Match(o.Template, g.Template); !ok {
+			return false
+		}
+	}
+	if o.Pages != "" {
+		for _, page := range g.Pages {
+			p := page.URL
+			if u, err := url.Parse(page.URL); err == nil {
+				p = u.Path
+			}
+			if ok, _ := path.Match(o.Pages, p); !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// A11yPolicy mirrors a11y-policy.toml
+type A11yPolicy struct {
+	File      []string                   `toml:"file"` // impacts that file issues
+	Warn      []string                   `toml:"warn"` // impacts that only warn
+	Defaults  A11yIssueFields            `toml:"defaults"`
+	Impact    map[string]A11yIssueFields `toml:"impact"`
+	WCAG      map[string]A11yIssueFields `toml:"wcag"` // keyed by A, AA, AAA or best-practice
+	Overrides []A11yRuleOverride         `toml:"override"`
+}
+
+// DefaultA11yPolicy files critical and serious violations as P1 bugs, the
+// behaviour before the policy file existed
+func DefaultA11yPolicy() *A11yPolicy {
+	priority := 1
+	return &A11yPolicy{
+		File:     []string{"critical", "serious"},
+		Defaults: A11yIssueFields{Priority: &priority, Type: "bug"},
+	}
+}
+
+// LoadA11yPolicy reads and validates a policy file. Defaults not set in the
+// file come from DefaultA11yPolicy.
+func LoadA11yPolicy(p string) (*A11yPolicy, error) {
+	var policy A11yPolicy
+	if _, err := toml.DecodeFile(p, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse a11y policy %s: %w", p, err)
+	}
+
+	defaults := DefaultA11yPolicy()
+	if policy.File == nil {
+		policy.File = defaults.File
+	}
+	if policy.Defaults.Priority == nil {
+		policy.Defaults.Priority = defaults.Defaults.Priority
+	}
+	if policy.Defaults.Type == "" {
+		policy.Defaults.Type = defaults.Defaults.Type
+	}
+
+	if err := policy.validate(); err != nil {
+		return nil, fmt.Errorf("a11y policy %s: %w", p, err)
+	}
+	return &policy, nil
+}
+
+// A11yPolicyFromFile loads A11yPolicyPath, falling back to DefaultA11yPolicy
+// when it does not exist or is invalid
+func A11yPolicyFromFile() *A11yPolicy {
+	policy, err := LoadA11yPolicy(A11yPolicyPath)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			fmt.Printf("Warning: %v; using the default a11y policy\n", err)
+		}
+		return DefaultA11yPolicy()
+	}
+	return policy
+}
+
+// validate checks impacts, levels, priorities and override patterns
+func (p *A11yPolicy) validate() error {
+	for _, impact := range append(append([]string(nil), p.File...), p.Warn...) {
+		if !containsString(axeImpacts, impact) {
+			return fmt.Errorf("unknown impact %q", impact)
+		}
+		if containsString(p.File, impact) && containsString(p.Warn, impact) {
+			return fmt.Errorf("impact %q is both filed and warned", impact)
+		}
+	}
+	if err := p.Defaults.validate(); err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+	for impact, fields := range p.Impact {
+		if !containsString(axeImpacts, impact) {
+			return fmt.Errorf("unknown impact %q", impact)
+		}
+		if err := fields.validate(); err != nil {
+			return fmt.Errorf("impact %s: %w", impact, err)
+		}
+	}
+	for level, fields := range p.WCAG {
+		if !containsString([]string{"A", "AA", "AAA", "best-practice"}, level) {
+			return fmt.Errorf("unknown WCAG level %q", level)
+		}
+		if err := fields.validate(); err != nil {
+			return fmt.Errorf("wcag %s: %w", level, err)
+		}
+	}
+
+	for i, o := range p.Overrides {
+		if o.Rule == "" && o.Template == "" && o.Pages == "" {
+			return fmt.Errorf("override %d needs a rule, template or pages", i+1)
+		}
+		for _, pattern := range []string{o.Rule, o.Template, o.Pages} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("override %d: invalid pattern %q: %w", i+1, pattern, err)
+			}
+		}
+		if o.Action != "" && o.Action != A11yFile && o.Action != A11yWarn && o.Action != A11yIgnore {
+			return fmt.Errorf("override %d: unknown action %q", i+1, o.Action)
+		}
+		if err := o.validate(); err != nil {
+			return fmt.Errorf("override %d: %w", i+1, err)
+		}
+		if strings.TrimSpace(o.Reason) == "" {
+			return fmt.Errorf("override %d needs a reason", i+1)
+		}
+	}
+	return nil
+}
+
+// A11yDecision is what the policy does with a group of violations
+type A11yDecision struct {
+	Action   string
+	Priority int
+	Type     string
+	Labels   []string
+	Reasons  []string // reasons of the overrides applied
+}
+
+// Decide resolves the action and tracker fields for a group: defaults, then
+// the impact, then the WCAG level, then every matching override in order
+func (p *A11yPolicy) Decide(g A11yGroup) A11yDecision {
+	d := A11yDecision{Action: A11yIgnore, Labels: []string{A11yIssueLabel}}
+	switch {
+	case containsString(p.File, g.Impact):
+		d.Action = A11yFile
+	case containsString(p.Warn, g.Impact):
+		d.Action = A11yWarn
+	}
+
+	d.apply(p.Defaults)
+	if fields, ok := p.Impact[g.Impact]; ok {
+		d.apply(fields)
+	}
+	if fields, ok := p.WCAG[WCAGLevel(g.Tags)]; ok {
+		d.apply(fields)
+	}
+	for _, o := range p.Overrides {
+		if !o.Matches(g) {
+			continue
+		}
+		if o.Action != "" {
+			d.Action = o.Action
+		}
+		d.apply(o.A11yIssueFields)
+		d.Reasons = append(d.Reasons, o.Reason)
+	}
+	return d
+}
+
+// apply layers fields over the decision
+func (d *A11yDecision) apply(fields A11yIssueFields) {
+	if fields.Priority != nil {
+		d.Priority = *fields.Priority
+	}
+	if fields.Type != "" {
+		d.Type = fields.Type
+	}
+	for _, label := range fields.Labels {
+		if !containsString(d.Labels, label) {
+			d.Labels = append(d.Labels, label)
+		}
+	}
+}
+
+// IssueFor returns the tracker issue proposed for a group
+func (p *A11yPolicy) IssueFor(g A11yGroup) Issue {
+	d := p.Decide(g)
+	return Issue{
+		Title:       g.IssueTitle(),
+		Description: g.Description(),
+		Status:      IssueOpen,
+		IssueType:   d.Type,
+		Priority:    d.Priority,
+		Labels:      d.Labels,
+	}
+}
+
+// wcagTagPattern matches axe conformance tags such as wcag2a or wcag21aa
+var wcagTagPattern = regexp.MustCompile(`^wcag\d+(a{1,3})$`)
+
+// WCAGLevel returns the conformance level an axe rule's tags map to: the
+// lowest of A, AA and AAA, "best-practice", or "" when untagged
+func WCAGLevel(tags []string) string {
+	level := ""
+	for _, tag := range tags {
+		m := wcagTagPattern.FindStringSubmatch(tag)
+		if m == nil {
+			continue
+		}
+		if candidate := strings.ToUpper(m[1]); level == "" || len(candidate) < len(level) {
+			level = candidate
+		}
+	}
+	if level == "" && containsString(tags, "best-practice") {
+		return "best-practice"
+	}
+	return level
+}
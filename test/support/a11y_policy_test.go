@@ -0,0 +1,116 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// policyGroup returns a group for one node failing rule on pageURL
+func policyGroup(rule, impact string, tags []string, pageURL string) A11yGroup {
+	return GroupOccurrences([]A11yOccurrence{{RuleID: rule, Impact: impact, Tags: tags, Selector: "main", URL: pageURL}})[0]
+}
+
+// TestWCAGLevel tests axe tags map to the lowest conformance level
+func TestWCAGLevel(t *testing.T) {
+	assert.Equal(t, "A", WCAGLevel([]string{"cat.text-alternatives", "wcag2a", "wcag111"}))
+	assert.Equal(t, "AA", WCAGLevel([]string{"wcag2aa", "wcag143"}))
+	assert.Equal(t, "A", WCAGLevel([]string{"wcag21aa", "wcag2a"}))
+	assert.Equal(t, "best-practice", WCAGLevel([]string{"cat.keyboard", "best-practice"}))
+	assert.Equal(t, "", WCAGLevel(nil))
+}
+
+// TestDefaultA11yPolicy tests the default files critical and serious as P1 bugs
+func TestDefaultA11yPolicy(t *testing.T) {
+	policy := DefaultA11yPolicy()
+
+	d := policy.Decide(policyGroup("image-alt", "critical", []string{"wcag2a"}, "/tools/bun/"))
+	assert.Equal(t, A11yDecision{Action: A11yFile, Priority: 1, Type: "bug", Labels: []string{A11yIssueLabel}}, d)
+
+	assert.Equal(t, A11yIgnore, policy.Decide(policyGroup("region", "moderate", nil, "/")).Action)
+}
+
+// TestLoadA11yPolicy tests layering of impact, WCAG level and overrides
+func TestLoadA11yPolicy(t *testing.T) {
+	t.Chdir("..")
+
+	policy, err := LoadA11yPolicy(A11yPolicyPath)
+	require.NoError(t, err)
+
+	d := policy.Decide(policyGroup("image-alt", "critical", []string{"wcag2a"}, "/tools/bun/"))
+	assert.Equal(t, A11yFile, d.Action)
+	assert.Equal(t, 0, d.Priority)
+	assert.Equal(t, []string{A11yIssueLabel, "wcag-a"}, d.Labels)
+
+	d = policy.Decide(policyGroup("region", "moderate", []string{"best-practice"}, "/"))
+	assert.Equal(t, A11yWarn, d.Action)
+	assert.Equal(t, 2, d.Priority)
+
+	assert.Equal(t, A11yIgnore, policy.Decide(policyGroup("region", "minor", nil, "/")).Action)
+
+	// color-contrast on component demos is demoted, elsewhere it is not
+	d = policy.Decide(policyGroup("color-contrast", "serious", []string{"wcag2aa"}, "http://localhost:1313/components/piano-demo/"))
+	assert.Equal(t, 3, d.Priority)
+	assert.Len(t, d.Reasons, 1)
+	assert.Equal(t, 1, policy.Decide(policyGroup("color-contrast", "serious", []string{"wcag2aa"}, "/tools/bun/")).Priority)
+
+	issue := policy.IssueFor(policyGroup("color-contrast", "serious", []string{"wcag2aa"}, "/components/demo/"))
+	assert.Equal(t, 3, issue.Priority)
+	assert.Equal(t, "bug", issue.IssueType)
+	assert.Equal(t, []string{A11yIssueLabel, "wcag-aa"}, issue.Labels)
+}
+
+// TestLoadA11yPolicy_Invalid tests invalid policies are rejected
+func TestLoadA11yPolicy_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"unknown impact": `file = ["severe"]`,
+		"filed and warned": `file = ["serious"]
+warn = ["serious"]`,
+		"between 0 and 4": `[impact.critical]
+priority = 5`,
+		"unknown WCAG level": `[wcag.AAAA]
+labels = ["x"]`,
+		"needs a reason": `[[override]]
+rule = "color-contrast"`,
+		"unknown action": `[[override]]
+rule = "region"
+action = "skip"
+reason = "x"`,
+	}
+	for want, content := range cases {
+		t.Run(want, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), A11yPolicyPath)
+			require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+			_, err := LoadA11yPolicy(p)
+			assert.ErrorContains(t, err, want)
+		})
+	}
+}
+
+// TestAccessibilityScanner_CreateBdIssuesForAll_Policy tests warned impacts are not filed
+func TestAccessibilityScanner_CreateBdIssuesForAll_Policy(t *testing.T) {
+	t.Setenv("CI", "")
+	tracker := NewMemoryTracker()
+	s := NewAccessibilityScanner("http://localhost:1313", tracker)
+	priority := 3
+	s.Policy = &A11yPolicy{
+		File:     []string{"critical"},
+		Warn:     []string{"serious"},
+		Defaults: A11yIssueFields{Priority: &priority, Type: "task"},
+	}
+
+	s.AddViolations("/tools/bun/", []AxeViolation{
+		{ID: "image-alt", Impact: "critical", Nodes: []AxeNode{{Target: []string{"img"}}}},
+		{ID: "color-contrast", Impact: "serious", Nodes: []AxeNode{{Target: []string{"p"}}}},
+	})
+	require.NoError(t, s.CreateBdIssuesForAll())
+
+	issues, err := tracker.List(IssueFilter{})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, 3, issues[0].Priority)
+	assert.Equal(t, "task", issues[0].IssueType)
+}
@@ -0,0 +1,184 @@
+package support
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Proposal files written in CI, relative to the artifacts directory
+const (
+	ProposalsFile        = "a11y-proposals.jsonl"
+	ProposalsSummaryFile = "a11y-proposals.md"
+)
+
+// Proposal kinds, from comparing a proposed issue with the tracker
+const (
+	ProposalCreate    = "create"
+	ProposalReopen    = "reopen"
+	ProposalUpdate    = "update"
+	ProposalAdopt     = "adopt"
+	ProposalUnchanged = "unchanged"
+)
+
+// ProposalAction is what filing a proposed issue does to the tracker
+type ProposalAction struct {
+	Kind  string
+	Issue Issue // the issue to create, or the existing issue with its new fields
+}
+
+// templateSuffixPattern strips the " (template)" suffix of grouped titles
+var templateSuffixPattern = regexp.MustCompile(` \([^()]*\)$`)
+
+// PlanProposal decides how to file a proposed issue given the tracker's
+// issues: dedup keys on the fingerprint, falling back to the title for open
+// issues filed before issues were keyed on rule and template
+func PlanProposal(issues []Issue, proposal Issue) ProposalAction {
+	fingerprint := FingerprintOf(proposal)
+	legacyTitle := templateSuffixPattern.ReplaceAllString(proposal.Title, "")
+
+	existing := findIssue(issues, fingerprint, proposal.Title, legacyTitle)
+	if existing == nil {
+		return ProposalAction{Kind: ProposalCreate, Issue: proposal}
+	}
+
+	updated := *existing
+	description, added := MergeAffectedPages(proposal.Description, existing.Description)
+	updated.Title = proposal.Title
+	updated.Description = description
+	updated.Priority = proposal.Priority
+	updated.Labels = proposal.Labels
+
+	switch {
+	case !existing.IsOpen():
+		updated.Status = IssueOpen
+		return ProposalAction{Kind: ProposalReopen, Issue: updated}
+	case FingerprintOf(*existing) != fingerprint:
+		return ProposalAction{Kind: ProposalAdopt, Issue: updated}
+	case added, updated.Priority != existing.Priority, !equalLabels(updated.Labels, existing.Labels):
+		return ProposalAction{Kind: ProposalUpdate, Issue: updated}
+	default:
+		return ProposalAction{Kind: ProposalUnchanged, Issue: *existing}
+	}
+}
+
+// equalLabels reports whether two label lists hold the same labels in any order
+func equalLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, label := range a {
+		if !containsString(b, label) {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyProposal carries out a planned action and returns the resulting issue
+func ApplyProposal(tracker IssueTracker, action ProposalAction) (Issue, error) {
+	switch action.Kind {
+	case ProposalCreate:
+		return tracker.Create(action.Issue)
+	case ProposalUnchanged:
+		return action.Issue, nil
+	default:
+		return action.Issue, tracker.Update(action.Issue)
+	}
+}
+
+// FileProposal plans and applies one proposed issue against the tracker
+func FileProposal(tracker IssueTracker, proposal Issue) (ProposalAction, error) {
+	issues, err := tracker.List(IssueFilter{})
+	if err != nil {
+		fmt.Printf("Warning: failed to check for existing issue: %v\n", err)
+	}
+	action := PlanProposal(issues, proposal)
+	issue, err := ApplyProposal(tracker, action)
+	action.Issue = issue
+	return action, err
+}
+
+// WriteProposals writes proposed issues as JSONL in the .beads issue schema
+// and a Markdown summary of what importing them would do against known
+func WriteProposals(dir string, proposals []Issue, known []Issue) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	var jsonl bytes.Buffer
+	for _, proposal := range proposals {
+		line, err := json.Marshal(proposal)
+		if err != nil {
+			return fmt.Errorf("failed to encode proposal %q: %w", proposal.Title, err)
+		}
+		jsonl.Write(line)
+		jsonl.WriteByte('\n')
+	}
+	if err := os.WriteFile(filepath.Join(dir, ProposalsFile), jsonl.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write proposals: %w", err)
+	}
+
+	summary := SummarizeProposals(proposals, known)
+	if err := os.WriteFile(filepath.Join(dir, ProposalsSummaryFile), []byte(summary), 0o644); err != nil {
+		return fmt.Errorf("failed to write proposals summary: %w", err)
+	}
+	return nil
+}
+
+// SummarizeProposals renders a Markdown table of proposals and the action
+// each would take against the known issues
+func SummarizeProposals(proposals []Issue, known []Issue) string {
+	var b strings.Builder
+	b.WriteString("# Accessibility issue proposals\n\n")
+	if len(proposals) == 0 {
+		b.WriteString("No serious or critical accessibility violations found.\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%d proposed issues. Import them locally with:\n\n", len(proposals))
+	fmt.Fprintf(&b, "```bash\ncd test && go run ./cmd/import-a11y-proposals %s\n```\n\n", ProposalsFile)
+	b.WriteString("| Action | Issue | Pages | Fingerprint |\n| --- | --- | ---: | --- |\n")
+	for _, proposal := range proposals {
+		action := PlanProposal(known, proposal)
+		target := proposal.Title
+		if action.Kind != ProposalCreate {
+			target = action.Issue.ID + ": " + target
+		}
+		fmt.Fprintf(&b, "| %s | %s | %d | %s |\n",
+			action.Kind, strings.ReplaceAll(target, "|", `\|`), countURLs(PagesInDescription(proposal.Description)), FingerprintOf(proposal))
+	}
+	return b.String()
+}
+
+// LoadProposals reads a proposals JSONL file
+func LoadProposals(path string) ([]Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proposals: %w", err)
+	}
+
+	var proposals []Issue
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		var proposal Issue
+		if err := json.Unmarshal(raw, &proposal); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if FingerprintOf(proposal) == "" {
+			return nil, fmt.Errorf("%s:%d: proposal %q has no fingerprint", path, line, proposal.Title)
+		}
+		proposals = append(proposals, proposal)
+	}
+	return proposals, scanner.Err()
+}
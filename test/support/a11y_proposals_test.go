@@ -0,0 +1,107 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// proposalFor returns the proposed issue for a card image violation on pages
+func proposalFor(pages ...string) Issue {
+	var occurrences []A11yOccurrence
+	for _, page := range pages {
+		occurrences = append(occurrences, OccurrencesFromViolations(page, []AxeViolation{cardImageViolation("img")})...)
+	}
+	return DefaultA11yPolicy().IssueFor(IssueGroups(GroupOccurrences(occurrences))[0])
+}
+
+// TestPlanProposal tests how a proposal is filed against existing issues
+func TestPlanProposal(t *testing.T) {
+	proposal := proposalFor("/tools/bun/")
+	existing := proposal
+	existing.ID = "pw-1"
+
+	action := PlanProposal(nil, proposal)
+	assert.Equal(t, ProposalCreate, action.Kind)
+
+	action = PlanProposal([]Issue{existing}, proposal)
+	assert.Equal(t, ProposalUnchanged, action.Kind)
+	assert.Equal(t, "pw-1", action.Issue.ID)
+
+	action = PlanProposal([]Issue{existing}, proposalFor("/tools/hugo/"))
+	assert.Equal(t, ProposalUpdate, action.Kind)
+	assert.Equal(t, []string{"/tools/bun/", "/tools/hugo/"}, pageURLs(PagesInDescription(action.Issue.Description)))
+
+	reprioritized := proposal
+	reprioritized.Priority = 3
+	action = PlanProposal([]Issue{existing}, reprioritized)
+	assert.Equal(t, ProposalUpdate, action.Kind, "policy changes update open issues")
+	assert.Equal(t, 3, action.Issue.Priority)
+
+	closed := existing
+	closed.Status = IssueClosed
+	action = PlanProposal([]Issue{closed}, proposal)
+	assert.Equal(t, ProposalReopen, action.Kind)
+	assert.Equal(t, IssueOpen, action.Issue.Status)
+
+	legacy := Issue{ID: "pw-2", Title: templateSuffixPattern.ReplaceAllString(proposal.Title, ""), Status: IssueOpen}
+	action = PlanProposal([]Issue{legacy}, proposal)
+	assert.Equal(t, ProposalAdopt, action.Kind)
+	assert.Equal(t, FingerprintOf(proposal), FingerprintOf(action.Issue))
+
+	perSelector := Issue{ID: "pw-3", Title: proposal.Title, Status: IssueOpen,
+		Description: WithFingerprint("old", A11yFingerprint("image-alt", "img", "/tools/bun/"))}
+	action = PlanProposal([]Issue{perSelector}, proposal)
+	assert.Equal(t, ProposalAdopt, action.Kind, "issues filed per selector adopt the rule and template fingerprint")
+	assert.Equal(t, FingerprintOf(proposal), FingerprintOf(action.Issue))
+}
+
+// TestFileProposal tests importing the same proposal twice files one issue
+func TestFileProposal(t *testing.T) {
+	tracker := NewMemoryTracker()
+
+	action, err := FileProposal(tracker, proposalFor("/tools/bun/"))
+	require.NoError(t, err)
+	assert.Equal(t, ProposalCreate, action.Kind)
+
+	action, err = FileProposal(tracker, proposalFor("/tools/docker/"))
+	require.NoError(t, err)
+	assert.Equal(t, ProposalUpdate, action.Kind)
+
+	issues, err := tracker.List(IssueFilter{})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Len(t, PagesInDescription(issues[0].Description), 2)
+}
+
+// TestWriteProposals tests the JSONL round trip and the Markdown summary
+func TestWriteProposals(t *testing.T) {
+	dir := t.TempDir()
+	proposals := []Issue{proposalFor("/tools/bun/", "/tools/hugo/")}
+	known := []Issue{{ID: "pw-1", Title: proposals[0].Title, Status: IssueClosed, Description: WithFingerprint("old", FingerprintOf(proposals[0]))}}
+
+	require.NoError(t, WriteProposals(dir, proposals, known))
+
+	loaded, err := LoadProposals(filepath.Join(dir, ProposalsFile))
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, proposals[0].Description, loaded[0].Description)
+	assert.Equal(t, "bug", loaded[0].IssueType)
+
+	summary, err := os.ReadFile(filepath.Join(dir, ProposalsSummaryFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(summary), "| reopen | pw-1: "+proposals[0].Title+" | 2 | "+FingerprintOf(proposals[0])+" |")
+	assert.Contains(t, string(summary), "go run ./cmd/import-a11y-proposals")
+}
+
+// TestLoadProposals_RequiresFingerprint tests proposals without a fingerprint are rejected
+func TestLoadProposals_RequiresFingerprint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ProposalsFile)
+	require.NoError(t, os.WriteFile(path, []byte(`{"title":"Manual issue","status":"open"}`+"\n"), 0o644))
+
+	_, err := LoadProposals(path)
+	assert.ErrorContains(t, err, "has no fingerprint")
+}
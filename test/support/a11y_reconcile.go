@@ -0,0 +1,141 @@
+package support
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Reconcile actions
+const (
+	ReconcileClose  = "close"
+	ReconcileReopen = "reopen"
+)
+
+// reconcileCloseReason starts the comment ApplyReconciliation closes issues
+// with; only issues closed this way are reopened when they regress
+const reconcileCloseReason = "No longer reproduced by the full-site accessibility scan"
+
+// closedByReconciliation reports whether an issue was closed automatically
+// rather than by a person, e.g. as a duplicate or won't fix
+func closedByReconciliation(issue Issue) bool {
+	return !issue.IsOpen() && strings.HasPrefix(issue.CloseReason, reconcileCloseReason)
+}
+
+// ReconcileAction is one planned change to an auto-created accessibility issue
+type ReconcileAction struct {
+	Kind        string
+	Issue       Issue
+	Fingerprint string
+	Comment     string
+}
+
+// String formats the action for dry-run output
+func (a ReconcileAction) String() string {
+	return fmt.Sprintf("%s %s (%s): %s", a.Kind, a.Issue.ID, a.Fingerprint, a.Issue.Title)
+}
+
+// ReconcilePlan is the set of changes a reconciliation makes or would make
+type ReconcilePlan struct {
+	Actions []ReconcileAction
+	DryRun  bool
+}
+
+// String lists the actions, one per line
+func (p *ReconcilePlan) String() string {
+	if len(p.Actions) == 0 {
+		return "no accessibility issues to close or reopen"
+	}
+	lines := make([]string, 0, len(p.Actions))
+	for _, action := range p.Actions {
+		lines = append(lines, "  - "+action.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PlanReconciliation compares a scan with the tracker: open fingerprinted
+// issues on a template the run scanned that no longer reproduce are closed,
+// and issues it closed that reproduce again are reopened. Issues on templates
+// the run did not scan, and issues closed for any other reason, are left alone.
+func PlanReconciliation(issues []Issue, run *A11yRun, now time.Time) *ReconcilePlan {
+	found := run.IssueFingerprints()
+	// Only the newest issue per fingerprint is reconciled
+	latest := make(map[string]*Issue)
+	for i := range issues {
+		fp := FingerprintOf(issues[i])
+		if fp == "" {
+			continue
+		}
+		if latest[fp] == nil {
+			latest[fp] = FindByFingerprint(issues, fp)
+		}
+	}
+
+	plan := &ReconcilePlan{}
+	date := now.Format("2006-01-02")
+	for fp, issue := range latest {
+		switch {
+		case issue.IsOpen() && !found[fp] && containsString(run.Templates, TemplateOf(*issue)):
+			plan.Actions = append(plan.Actions, ReconcileAction{
+				Kind:        ReconcileClose,
+				Issue:       *issue,
+				Fingerprint: fp,
+				Comment:     fmt.Sprintf("%s on %s.", reconcileCloseReason, date),
+			})
+		case closedByReconciliation(*issue) && found[fp]:
+			plan.Actions = append(plan.Actions, ReconcileAction{
+				Kind:        ReconcileReopen,
+				Issue:       *issue,
+				Fingerprint: fp,
+				Comment:     fmt.Sprintf("Reopened %s: the accessibility scan reproduced this violation again.", date),
+			})
+		}
+	}
+
+	sort.Slice(plan.Actions, func(i, j int) bool { return plan.Actions[i].Issue.ID < plan.Actions[j].Issue.ID })
+	return plan
+}
+
+// ApplyReconciliation closes and reopens issues per the plan. Reopened issues
+// get the comment appended to their description.
+func ApplyReconciliation(tracker IssueTracker, plan *ReconcilePlan) error {
+	for _, action := range plan.Actions {
+		switch action.Kind {
+		case ReconcileClose:
+			if err := tracker.Close(action.Issue.ID, action.Comment); err != nil {
+				return err
+			}
+		case ReconcileReopen:
+			issue := action.Issue
+			issue.Status = IssueOpen
+			issue.Description = strings.TrimRight(issue.Description, "\n") + "\n\n" + action.Comment
+			if err := tracker.Update(issue); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Reconcile plans the reconciliation of the tracker's issues against a run
+// and, unless dryRun, applies it. The plan is printed either way.
+func Reconcile(tracker IssueTracker, run *A11yRun, dryRun bool) (*ReconcilePlan, error) {
+	issues, err := tracker.List(IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues for reconciliation: %w", err)
+	}
+
+	plan := PlanReconciliation(issues, run, time.Now())
+	plan.DryRun = dryRun
+	if plan.DryRun {
+		fmt.Printf("Accessibility issue reconciliation (dry run):\n%s\n", plan)
+		return plan, nil
+	}
+
+	if err := ApplyReconciliation(tracker, plan); err != nil {
+		return plan, err
+	}
+	fmt.Printf("Accessibility issue reconciliation:\n%s\n", plan)
+	return plan, nil
+}
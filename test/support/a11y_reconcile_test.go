@@ -0,0 +1,85 @@
+package support
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlanReconciliation tests fixed issues close, regressions reopen, and
+// manual, won't-fix and unscanned issues are untouched
+func TestPlanReconciliation(t *testing.T) {
+	fixed := A11yIssueFingerprint("image-alt", "tools/single")
+	regressed := A11yIssueFingerprint("color-contrast", "blog/list")
+	wontFix := A11yIssueFingerprint("region", "components/single")
+	unscanned := A11yIssueFingerprint("image-alt", "blog/single")
+	tracker := NewMemoryTracker(
+		Issue{ID: "pw-1", Title: "Accessibility Issue: Images", Description: WithFingerprint("Template: tools/single", fixed), Status: IssueOpen},
+		Issue{ID: "pw-2", Title: "Accessibility Issue: Contrast", Description: WithFingerprint("Template: blog/list", regressed), Status: IssueClosed,
+			CloseReason: reconcileCloseReason + " on 2026-09-01."},
+		Issue{ID: "pw-3", Title: "Manual accessibility audit", Status: IssueOpen},
+		Issue{ID: "pw-4", Title: "Accessibility Issue: Landmarks", Description: WithFingerprint("Template: components/single", wontFix), Status: IssueClosed,
+			CloseReason: "Won't fix: the demo is intentionally unstyled"},
+		Issue{ID: "pw-5", Title: "Accessibility Issue: Post images", Description: WithFingerprint("Template: blog/single", unscanned), Status: IssueOpen},
+	)
+	issues, err := tracker.List(IssueFilter{})
+	require.NoError(t, err)
+
+	var occurrences []A11yOccurrence
+	occurrences = append(occurrences, OccurrencesFromViolations("/blog/", []AxeViolation{{ID: "color-contrast", Impact: "serious", Nodes: []AxeNode{{Target: []string{".card p"}}}}})...)
+	occurrences = append(occurrences, OccurrencesFromViolations("/components/piano-demo/", []AxeViolation{{ID: "region", Impact: "moderate", Nodes: []AxeNode{{Target: []string{"body > div"}}}}})...)
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	run := NewA11yRun(GroupOccurrences(occurrences), now)
+	run.Templates = []string{"blog/list", "components/single", "tools/single"}
+
+	plan := PlanReconciliation(issues, run, now)
+	require.Len(t, plan.Actions, 2)
+	assert.Equal(t, ReconcileClose, plan.Actions[0].Kind)
+	assert.Equal(t, fixed, plan.Actions[0].Fingerprint)
+	assert.Contains(t, plan.Actions[0].Comment, "2026-10-18")
+	assert.Equal(t, ReconcileReopen, plan.Actions[1].Kind)
+	assert.Equal(t, "pw-2", plan.Actions[1].Issue.ID)
+	assert.Contains(t, plan.String(), "close pw-1 ("+fixed+")")
+
+	require.NoError(t, ApplyReconciliation(tracker, plan))
+	closed, _ := tracker.Get("pw-1")
+	assert.Equal(t, IssueClosed, closed.Status)
+	assert.Contains(t, closed.CloseReason, "No longer reproduced")
+	reopened, _ := tracker.Get("pw-2")
+	assert.Equal(t, IssueOpen, reopened.Status)
+	assert.Equal(t, regressed, FingerprintOf(reopened))
+	assert.Contains(t, reopened.Description, "reproduced this violation again")
+	manual, _ := tracker.Get("pw-3")
+	assert.Equal(t, IssueOpen, manual.Status)
+	closedByHand, _ := tracker.Get("pw-4")
+	assert.Equal(t, IssueClosed, closedByHand.Status, "issues closed by a person stay closed")
+	notScanned, _ := tracker.Get("pw-5")
+	assert.Equal(t, IssueOpen, notScanned.Status, "issues on templates the run did not scan stay open")
+}
+
+// TestAccessibilityScanner_ReconcileIssues_DryRun tests a dry run changes nothing
+func TestAccessibilityScanner_ReconcileIssues_DryRun(t *testing.T) {
+	t.Setenv("CI", "")
+	fixed := A11yIssueFingerprint("image-alt", "tools/single")
+	tracker := NewMemoryTracker(
+		Issue{ID: "pw-1", Title: "Accessibility Issue: Images", Description: WithFingerprint("Template: tools/single", fixed), Status: IssueOpen},
+		Issue{ID: "pw-2", Title: "Accessibility Issue: Post images", Description: WithFingerprint("Template: blog/single", A11yIssueFingerprint("image-alt", "blog/single")), Status: IssueOpen},
+	)
+	s := NewAccessibilityScanner("http://localhost:1313", tracker)
+	s.AddViolations("http://localhost:1313/tools/bun/", nil)
+
+	plan, err := s.ReconcileIssues(true)
+	require.NoError(t, err)
+	assert.True(t, plan.DryRun)
+	require.Len(t, plan.Actions, 1, "a clean scan of tool pages only closes the tool page issue")
+	issue, _ := tracker.Get("pw-1")
+	assert.Equal(t, IssueOpen, issue.Status)
+
+	plan, err = s.ReconcileIssues(false)
+	require.NoError(t, err)
+	assert.False(t, plan.DryRun)
+	issue, _ = tracker.Get("pw-1")
+	assert.Equal(t, IssueClosed, issue.Status)
+}
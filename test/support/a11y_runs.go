@@ -0,0 +1,178 @@
+package support
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A11yRunFile is where the scanner saves a run, relative to the artifacts directory
+const A11yRunFile = "a11y-run.json"
+
+// A11yBaselinePath is the base run compared against when A11Y_BASELINE is unset
+const A11yBaselinePath = "a11y-baseline.json"
+
+// A11yRun is the full result of an accessibility scan, keyed by fingerprint
+type A11yRun struct {
+	CreatedAt time.Time `json:"created_at"`
+	// FullSite marks a scan of every page rather than the pages a suite visits
+	FullSite bool `json:"full_site,omitempty"`
+	// Templates are the page templates scanned, including clean ones
+	Templates  []string             `json:"templates,omitempty"`
+	Violations map[string]A11yGroup `json:"violations"`
+}
+
+// NewA11yRun records grouped violations as a run
+func NewA11yRun(groups []A11yGroup, now time.Time) *A11yRun {
+	run := &A11yRun{CreatedAt: now.UTC(), Violations: make(map[string]A11yGroup, len(groups))}
+	for _, group := range groups {
+		run.Violations[group.Fingerprint] = group
+	}
+	return run
+}
+
+// Save writes the run as indented JSON, creating the directory if needed
+func (r *A11yRun) Save(p string) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(p), err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode accessibility run: %w", err)
+	}
+	if err := os.WriteFile(p, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write accessibility run: %w", err)
+	}
+	return nil
+}
+
+// LoadA11yRun reads a run saved by A11yRun.Save
+func LoadA11yRun(p string) (*A11yRun, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read accessibility run: %w", err)
+	}
+	var run A11yRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse accessibility run %s: %w", p, err)
+	}
+	if run.Violations == nil {
+		run.Violations = make(map[string]A11yGroup)
+	}
+	return &run, nil
+}
+
+// A11yBaselineFromEnv returns the base run path: A11Y_BASELINE, or
+// A11yBaselinePath. explicit reports whether A11Y_BASELINE was set.
+func A11yBaselineFromEnv() (p string, explicit bool) {
+	if p := os.Getenv("A11Y_BASELINE"); p != "" {
+		return p, true
+	}
+	return A11yBaselinePath, false
+}
+
+// Groups returns the run's groups sorted by rule, template and fingerprint
+func (r *A11yRun) Groups() []A11yGroup {
+	groups := make([]A11yGroup, 0, len(r.Violations))
+	for _, group := range r.Violations {
+		groups = append(groups, group)
+	}
+	sortGroups(groups)
+	return groups
+}
+
+// IssueFingerprints returns the fingerprints of the issues the run's groups
+// are filed under. Warned and ignored groups are included: they still
+// reproduce, and a policy change is not a fix.
+func (r *A11yRun) IssueFingerprints() map[string]bool {
+	found := make(map[string]bool)
+	for _, group := range IssueGroups(r.Groups()) {
+		found[group.Fingerprint] = true
+	}
+	return found
+}
+
+// OnTemplates returns the part of the run found on the given page templates,
+// so a partial scan is only compared with what it covered
+func (r *A11yRun) OnTemplates(templates []string) *A11yRun {
+	subset := &A11yRun{CreatedAt: r.CreatedAt, Violations: make(map[string]A11yGroup)}
+	for _, template := range r.Templates {
+		if containsString(templates, template) {
+			subset.Templates = append(subset.Templates, template)
+		}
+	}
+	for fp, group := range r.Violations {
+		if containsString(templates, group.Template) {
+			subset.Violations[fp] = group
+		}
+	}
+	return subset
+}
+
+// A11yRunDiff is how a head run differs from a base run
+type A11yRunDiff struct {
+	New       []A11yGroup // only in head
+	Fixed     []A11yGroup // only in base
+	Unchanged []A11yGroup // in both, as found by head
+}
+
+// CompareA11yRuns diffs two runs by fingerprint. When head records the
+// templates it scanned, base is limited to them, so templates head did not
+// visit are not reported as fixed.
+func CompareA11yRuns(base, head *A11yRun) A11yRunDiff {
+	if len(head.Templates) > 0 {
+		base = base.OnTemplates(head.Templates)
+	}
+
+	var diff A11yRunDiff
+	for fp, group := range head.Violations {
+		if _, ok := base.Violations[fp]; ok {
+			diff.Unchanged = append(diff.Unchanged, group)
+		} else {
+			diff.New = append(diff.New, group)
+		}
+	}
+	for fp, group := range base.Violations {
+		if _, ok := head.Violations[fp]; !ok {
+			diff.Fixed = append(diff.Fixed, group)
+		}
+	}
+
+	for _, groups := range [][]A11yGroup{diff.New, diff.Fixed, diff.Unchanged} {
+		sortGroups(groups)
+	}
+	return diff
+}
+
+// sortGroups orders groups by rule, template and fingerprint
+func sortGroups(groups []A11yGroup) {
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].RuleID != groups[j].RuleID {
+			return groups[i].RuleID < groups[j].RuleID
+		}
+		if groups[i].Template != groups[j].Template {
+			return groups[i].Template < groups[j].Template
+		}
+		return groups[i].Fingerprint < groups[j].Fingerprint
+	})
+}
+
+// String reports the counts, then one line per group: + new, - fixed, = unchanged
+func (d A11yRunDiff) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d new, %d fixed, %d unchanged accessibility violations", len(d.New), len(d.Fixed), len(d.Unchanged))
+	for _, section := range []struct {
+		marker string
+		groups []A11yGroup
+	}{{"+", d.New}, {"-", d.Fixed}, {"=", d.Unchanged}} {
+		for _, g := range section.groups {
+			fmt.Fprintf(&b, "\n  %s %s (%s) on %s at %s [%d pages, %s]",
+				section.marker, g.RuleID, g.Impact, g.Template, g.Selector, len(g.Pages), g.Fingerprint)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package support
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestA11yRun_SaveLoad tests runs round-trip through JSON keyed by fingerprint
+func TestA11yRun_SaveLoad(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	run := NewA11yRun(GroupOccurrences(OccurrencesFromViolations("/tools/bun/", []AxeViolation{cardImageViolation("img")})), now)
+	p := filepath.Join(t.TempDir(), "results", A11yRunFile)
+
+	require.NoError(t, run.Save(p))
+	loaded, err := LoadA11yRun(p)
+	require.NoError(t, err)
+	assert.Equal(t, run, loaded)
+	for fp, group := range loaded.Violations {
+		assert.Equal(t, fp, group.Fingerprint)
+	}
+}
+
+// TestCompareA11yRuns tests new, fixed and unchanged violations
+func TestCompareA11yRuns(t *testing.T) {
+	region := AxeViolation{ID: "region", Impact: "moderate", Nodes: []AxeNode{{Target: []string{"main"}}}}
+	contrast := AxeViolation{ID: "color-contrast", Impact: "serious", Nodes: []AxeNode{{Target: []string{".card-title"}}}}
+
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	base := NewA11yRun(GroupOccurrences(OccurrencesFromViolations("/tools/bun/", []AxeViolation{cardImageViolation("img"), region})), now)
+	// The image fix landed, a contrast bug came in; region moved pages but is the same template
+	head := NewA11yRun(GroupOccurrences(OccurrencesFromViolations("/tools/hugo/", []AxeViolation{region, contrast})), now)
+
+	diff := CompareA11yRuns(base, head)
+	require.Len(t, diff.New, 1)
+	assert.Equal(t, "color-contrast", diff.New[0].RuleID)
+	require.Len(t, diff.Fixed, 1)
+	assert.Equal(t, "image-alt", diff.Fixed[0].RuleID)
+	require.Len(t, diff.Unchanged, 1)
+	assert.Equal(t, "region", diff.Unchanged[0].RuleID)
+
+	report := diff.String()
+	assert.Contains(t, report, "1 new, 1 fixed, 1 unchanged accessibility violations")
+	assert.Contains(t, report, "+ color-contrast (serious) on tools/single at .card-title")
+	assert.Contains(t, report, "- image-alt (critical)")
+	assert.Contains(t, report, "= region (moderate)")
+}
+
+// TestA11yRun_OnTemplates tests partial scans only compare the templates they visited
+func TestA11yRun_OnTemplates(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	occurrences := append(OccurrencesFromViolations("/tools/bun/", []AxeViolation{cardImageViolation("img")}),
+		OccurrencesFromViolations("/", []AxeViolation{cardImageViolation("img")})...)
+	base := NewA11yRun(GroupOccurrences(occurrences), now)
+	head := NewA11yRun(GroupOccurrences(OccurrencesFromViolations("/", []AxeViolation{cardImageViolation("img")})), now)
+
+	diff := CompareA11yRuns(base.OnTemplates([]string{"home"}), head)
+	assert.Empty(t, diff.Fixed, "tool pages were not scanned")
+	assert.Len(t, diff.Unchanged, 1)
+}
+
+// TestCompareA11yRuns_HeadTemplates tests the base run is limited to the
+// templates the head run recorded
+func TestCompareA11yRuns_HeadTemplates(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	occurrences := append(OccurrencesFromViolations("/tools/bun/", []AxeViolation{cardImageViolation("img")}),
+		OccurrencesFromViolations("/", []AxeViolation{cardImageViolation("img")})...)
+	base := NewA11yRun(GroupOccurrences(occurrences), now)
+	base.Templates = []string{"home", "tools/single"}
+	// A clean scan of the home page only
+	head := &A11yRun{Templates: []string{"home"}, Violations: map[string]A11yGroup{}}
+
+	diff := CompareA11yRuns(base, head)
+	require.Len(t, diff.Fixed, 1, "only the home page was scanned")
+	assert.Equal(t, "home", diff.Fixed[0].Template)
+	assert.Equal(t, []string{"home"}, base.OnTemplates(head.Templates).Templates)
+}
+
+// TestAccessibilityScanner_SaveRun tests CI saves the full run with the proposals
+func TestAccessibilityScanner_SaveRun(t *testing.T) {
+	t.Setenv("CI", "true")
+	s := NewAccessibilityScanner("http://localhost:1313", NewMemoryTracker())
+	s.ProposalsDir = t.TempDir()
+	s.AddViolations("/tools/bun/", []AxeViolation{{ID: "region", Impact: "moderate", Nodes: []AxeNode{{Target: []string{"main"}}}}})
+
+	require.NoError(t, s.CreateBdIssuesForAll())
+
+	run, err := LoadA11yRun(filepath.Join(s.ProposalsDir, A11yRunFile))
+	require.NoError(t, err)
+	assert.Len(t, run.Violations, 1, "unfiled impacts are still recorded")
+}
+
+// TestAccessibilityScanner_Scanned tests a clean scan still yields a run to use as a baseline
+func TestAccessibilityScanner_Scanned(t *testing.T) {
+	s := NewAccessibilityScanner("http://localhost:1313", NewMemoryTracker())
+	s.ProposalsDir = t.TempDir()
+	assert.False(t, s.Scanned())
+
+	s.AddViolations("http://localhost:41234/tools/bun/", nil)
+	assert.True(t, s.Scanned())
+	assert.False(t, s.HasFindings())
+
+	_, err := s.SaveRun(s.RunPath())
+	require.NoError(t, err)
+	run, err := LoadA11yRun(filepath.Join(s.ProposalsDir, A11yRunFile))
+	require.NoError(t, err)
+	assert.Empty(t, run.Violations)
+}
@@ -0,0 +1,229 @@
+package support
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// A11yWaiversPath lists known accessibility violations that may not fail tests
+const A11yWaiversPath = "a11y-waivers.toml"
+
+// A11yWaiver is one [[waiver]] entry
+type A11yWaiver struct {
+	Rule          string    `toml:"rule"`     // axe rule ID, may use path.Match wildcards
+	URL           string    `toml:"url"`      // URL path pattern; empty matches every page
+	Selector      string    `toml:"selector"` // node selector pattern; empty matches every node
+	Justification string    `toml:"justification"`
+	Owner         string    `toml:"owner"`
+	Expires       time.Time `toml:"expires"`
+}
+
+// String identifies the waiver in reports
+func (w A11yWaiver) String() string {
+	scope := w.Rule
+	if w.URL != "" {
+		scope += " on " + w.URL
+	}
+	if w.Selector != "" {
+		scope += " at " + w.Selector
+	}
+	return fmt.Sprintf("%s (owner %s, expires %s)", scope, w.Owner, w.Expires.Format("2006-01-02"))
+}
+
+// Expired reports whether the waiver has lapsed; it is valid through its expiry date
+func (w A11yWaiver) Expired(now time.Time) bool {
+	return !now.Before(w.Expires.AddDate(0, 0, 1))
+}
+
+// Matches reports whether the waiver covers a failing node on a page
+func (w A11yWaiver) Matches(ruleID, pageURL, selector string) bool {
+	if ok, _ := path.Match(w.Rule, ruleID); !ok {
+		return false
+	}
+	if w.URL != "" {
+		p := pageURL
+		if u, err := url.Parse(pageURL); err == nil {
+			p = u.Path
+		}
+		if ok, _ := path.Match(w.URL, p); !ok {
+			return false
+		}
+	}
+	if w.Selector != "" {
+		if ok, _ := path.Match(w.Selector, selector); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// A11yWaivers mirrors a11y-waivers.toml
+type A11yWaivers struct {
+	Waivers []A11yWaiver `toml:"waiver"`
+}
+
+// LoadA11yWaivers reads and validates a waiver file; a missing file waives nothing
+func LoadA11yWaivers(p string) (*A11yWaivers, error) {
+	var waivers A11yWaivers
+	if _, err := toml.DecodeFile(p, &waivers); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &waivers, nil
+		}
+		return nil, fmt.Errorf("failed to parse a11y waivers %s: %w", p, err)
+	}
+
+	for i, w := range waivers.Waivers {
+		if w.Rule == "" {
+			return nil, fmt.Errorf("a11y waivers %s: waiver %d has no rule", p, i+1)
+		}
+		for _, pattern := range []string{w.Rule, w.URL, w.Selector} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("a11y waivers %s: waiver %d: invalid pattern %q: %w", p, i+1, pattern, err)
+			}
+		}
+		if strings.TrimSpace(w.Justification) == "" || strings.TrimSpace(w.Owner) == "" {
+			return nil, fmt.Errorf("a11y waivers %s: waiver for %q needs a justification and an owner", p, w.Rule)
+		}
+		if w.Expires.IsZero() {
+			return nil, fmt.Errorf("a11y waivers %s: waiver for %q needs an expiry date", p, w.Rule)
+		}
+	}
+	return &waivers, nil
+}
+
+// Expired returns the waivers that have lapsed
+func (ws *A11yWaivers) Expired(now time.Time) []A11yWaiver {
+	var expired []A11yWaiver
+	for _, w := range ws.Waivers {
+		if w.Expired(now) {
+			expired = append(expired, w)
+		}
+	}
+	return expired
+}
+
+// A11yWaived counts the nodes of one rule a waiver covered on a page
+type A11yWaived struct {
+	Waiver A11yWaiver
+	RuleID string
+	Nodes  int
+}
+
+// A11yResult is the axe violations on a page after waivers
+type A11yResult struct {
+	URL        string
+	Violations []AxeViolation // what is left once waived nodes are removed
+	Waived     []A11yWaived
+	// Expired are lapsed waivers that would have covered a violation; the
+	// violation is kept and the waiver needs renewing or removing
+	Expired []A11yWaiver
+}
+
+// Apply removes waived nodes from violations found on pageURL. Violations
+// left with no nodes are dropped; expired waivers waive nothing.
+func (ws *A11yWaivers) Apply(pageURL string, violations []AxeViolation, now time.Time) A11yResult {
+	result := A11yResult{URL: pageURL}
+	waived := make(map[string]*A11yWaived)
+	var order []string
+	expired := make(map[string]bool)
+
+	for _, v := range violations {
+		kept := v
+		kept.Nodes = nil
+		for _, node := range v.Nodes {
+			w, lapsed := ws.match(v.ID, pageURL, strings.Join(node.Target, " "), now)
+			if lapsed != nil && !expired[lapsed.String()] {
+				expired[lapsed.String()] = true
+				result.Expired = append(result.Expired, *lapsed)
+			}
+			if w == nil {
+				kept.Nodes = append(kept.Nodes, node)
+				continue
+			}
+			key := v.ID + "\n" + w.String()
+			if waived[key] == nil {
+				waived[key] = &A11yWaived{Waiver: *w, RuleID: v.ID}
+				order = append(order, key)
+			}
+			waived[key].Nodes++
+		}
+		if len(kept.Nodes) > 0 {
+			result.Violations = append(result.Violations, kept)
+		}
+	}
+
+	for _, key := range order {
+		result.Waived = append(result.Waived, *waived[key])
+	}
+	return result
+}
+
+// match returns the first current waiver covering a node, and the first
+// expired one when no current waiver does
+func (ws *A11yWaivers) match(ruleID, pageURL, selector string, now time.Time) (*A11yWaiver, *A11yWaiver) {
+	var lapsed *A11yWaiver
+	for i := range ws.Waivers {
+		w := &ws.Waivers[i]
+		if !w.Matches(ruleID, pageURL, selector) {
+			continue
+		}
+		if !w.Expired(now) {
+			return w, nil
+		}
+		if lapsed == nil {
+			lapsed = w
+		}
+	}
+	return nil, lapsed
+}
+
+// WithImpact returns the remaining violations of one impact
+func (r A11yResult) WithImpact(impact string) []AxeViolation {
+	var matched []AxeViolation
+	for _, v := range r.Violations {
+		if v.Impact == impact {
+			matched = append(matched, v)
+		}
+	}
+	return matched
+}
+
+// WaivedNodes counts the waived nodes
+func (r A11yResult) WaivedNodes() int {
+	total := 0
+	for _, w := range r.Waived {
+		total += w.Nodes
+	}
+	return total
+}
+
+// WaivedSummary renders one line per waived rule, sorted, for logs
+func (r A11yResult) WaivedSummary() string {
+	lines := make([]string, 0, len(r.Waived))
+	for _, w := range r.Waived {
+		lines = append(lines, fmt.Sprintf("\n  ~ %s [%d nodes] waived by %s: %s", w.RuleID, w.Nodes, w.Waiver, w.Waiver.Justification))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "")
+}
+
+// ExpiredError fails when a lapsed waiver matched, naming each one
+func (r A11yResult) ExpiredError() error {
+	if len(r.Expired) == 0 {
+		return nil
+	}
+	lines := make([]string, 0, len(r.Expired))
+	for _, w := range r.Expired {
+		lines = append(lines, "\n  - "+w.String())
+	}
+	return fmt.Errorf("%d accessibility waivers expired on %s, renew or remove them in %s:%s",
+		len(r.Expired), r.URL, A11yWaiversPath, strings.Join(lines, ""))
+}
@@ -0,0 +1,128 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestA11yWaiver_Expired tests a waiver is valid through its expiry date
+func TestA11yWaiver_Expired(t *testing.T) {
+	w := A11yWaiver{
+		Rule:          "color-contrast",
+		URL:           "/components/*demo*/",
+		Justification: "sample palettes",
+		Owner:         "pwarnock",
+		Expires:       time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+	assert.False(t, w.Expired(time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC)))
+	assert.True(t, w.Expired(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+// TestA11yWaivers_Apply tests waived nodes are removed and counted
+func TestA11yWaivers_Apply(t *testing.T) {
+	waivers := &A11yWaivers{Waivers: []A11yWaiver{{
+		Rule:          "color-contrast",
+		URL:           "/components/*demo*/",
+		Justification: "sample palettes",
+		Owner:         "pwarnock",
+		Expires:       time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+	}}}
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	violations := []AxeViolation{
+		{ID: "color-contrast", Impact: "serious", Nodes: []AxeNode{{Target: []string{".key"}}, {Target: []string{".label"}}}},
+		{ID: "image-alt", Impact: "critical", Nodes: []AxeNode{{Target: []string{"img"}}}},
+	}
+
+	result := waivers.Apply("http://localhost:1313/components/piano-demo/", violations, now)
+	require.Len(t, result.Violations, 1)
+	assert.Equal(t, "image-alt", result.Violations[0].ID)
+	assert.Equal(t, 2, result.WaivedNodes())
+	assert.Empty(t, result.WithImpact("serious"))
+	assert.Contains(t, result.WaivedSummary(), "color-contrast [2 nodes] waived by color-contrast on /components/*demo*/")
+	assert.NoError(t, result.ExpiredError())
+
+	// Other pages are not covered
+	result = waivers.Apply("http://localhost:1313/tools/bun/", violations, now)
+	assert.Len(t, result.Violations, 2)
+	assert.Zero(t, result.WaivedNodes())
+}
+
+// TestA11yWaivers_Apply_Selector tests selector patterns narrow a waiver to some nodes
+func TestA11yWaivers_Apply_Selector(t *testing.T) {
+	waivers := &A11yWaivers{Waivers: []A11yWaiver{{
+		Rule:          "color-contrast",
+		URL:           "/components/*demo*/",
+		Selector:      ".key*",
+		Justification: "sample palettes",
+		Owner:         "pwarnock",
+		Expires:       time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+	}}}
+	violations := []AxeViolation{{ID: "color-contrast", Nodes: []AxeNode{{Target: []string{".key-black"}}, {Target: []string{".label"}}}}}
+
+	result := waivers.Apply("/components/demo/", violations, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	require.Len(t, result.Violations, 1)
+	assert.Equal(t, []AxeNode{{Target: []string{".label"}}}, result.Violations[0].Nodes)
+	assert.Equal(t, 1, result.WaivedNodes())
+}
+
+// TestA11yWaivers_Apply_Expired tests expired waivers waive nothing and are reported
+func TestA11yWaivers_Apply_Expired(t *testing.T) {
+	waivers := &A11yWaivers{Waivers: []A11yWaiver{{
+		Rule:          "color-contrast",
+		URL:           "/components/*demo*/",
+		Justification: "sample palettes",
+		Owner:         "pwarnock",
+		Expires:       time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+	}}}
+	violations := []AxeViolation{{ID: "color-contrast", Impact: "serious", Nodes: []AxeNode{{Target: []string{".key"}}}}}
+
+	result := waivers.Apply("/components/piano-demo/", violations, time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC))
+	assert.Len(t, result.Violations, 1)
+	assert.Zero(t, result.WaivedNodes())
+	assert.ErrorContains(t, result.ExpiredError(), "owner pwarnock, expires 2026-12-31")
+}
+
+// TestLoadA11yWaivers tests the committed waivers are valid
+func TestLoadA11yWaivers(t *testing.T) {
+	t.Chdir("..")
+
+	waivers, err := LoadA11yWaivers(A11yWaiversPath)
+	require.NoError(t, err)
+	require.NotEmpty(t, waivers.Waivers)
+	for _, w := range waivers.Waivers {
+		assert.NotEmpty(t, w.Owner)
+		assert.False(t, w.Expires.IsZero())
+	}
+}
+
+// TestLoadA11yWaivers_Invalid tests waivers need a justification, owner and expiry
+func TestLoadA11yWaivers_Invalid(t *testing.T) {
+	p := filepath.Join(t.TempDir(), A11yWaiversPath)
+
+	require.NoError(t, os.WriteFile(p, []byte("[[waiver]]\nrule = \"region\"\njustification = \"x\"\nowner = \"me\"\n"), 0o644))
+	_, err := LoadA11yWaivers(p)
+	assert.ErrorContains(t, err, "needs an expiry date")
+
+	require.NoError(t, os.WriteFile(p, []byte("[[waiver]]\nrule = \"region\"\nexpires = 2027-01-01\n"), 0o644))
+	_, err = LoadA11yWaivers(p)
+	assert.ErrorContains(t, err, "needs a justification and an owner")
+
+	waivers, err := LoadA11yWaivers(filepath.Join(t.TempDir(), "missing.toml"))
+	require.NoError(t, err)
+	assert.Empty(t, waivers.Waivers)
+}
+
+// TestWCAGTagsFor tests versions and levels include the ones below them
+func TestWCAGTagsFor(t *testing.T) {
+	tags, err := WCAGTagsFor("2.1", "AA")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"wcag2a", "wcag2aa", "wcag21a", "wcag21aa"}, tags)
+
+	_, err = WCAGTagsFor("3.0", "AA")
+	assert.Error(t, err)
+}
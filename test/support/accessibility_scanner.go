@@ -1,111 +1,255 @@
 package support
 
 import (
-	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
-	"os/exec"
-	"strings"
+	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/pwarnock/go-playwright-testkit/pkg/scanner"
 )
 
-// AccessibilityScanner wraps the library scanner with bd-specific functionality
+// AccessibilityScanner wraps the library scanner and files its findings in an IssueTracker
 type AccessibilityScanner struct {
 	*scanner.AccessibilityScanner
+	Tracker IssueTracker
+	// Policy decides which impacts are filed and with what priority
+	Policy *A11yPolicy
+	// CIMode writes proposals to ProposalsDir instead of changing the tracker
+	CIMode       bool
+	ProposalsDir string
+	// FullSite marks saved runs as a scan of every page, which
+	// reconcile-a11y-issues requires; set it only after crawling the whole site
+	FullSite bool
+
+	occurrences []A11yOccurrence
+	templates   []string // page templates recorded by AddViolations, clean or not
+	proposals   []Issue
+	known       []Issue // the tracker's issues, listed once for the proposals summary
+	knownListed bool
+	sources     *SourceMap
+	sourcesErr  error
 }
 
-// NewAccessibilityScanner creates a new accessibility scanner
-func NewAccessibilityScanner(baseURL string) *AccessibilityScanner {
+// NewAccessibilityScanner creates a new accessibility scanner filing issues in
+// tracker, or through the bd CLI when tracker is nil, per a11y-policy.toml.
+// CI mode is on when CI is set; proposals go to PW_ARTIFACTS_DIR (default
+// test-results).
+func NewAccessibilityScanner(baseURL string, tracker IssueTracker) *AccessibilityScanner {
+	if tracker == nil {
+		tracker = NewBdTracker()
+	}
 	return &AccessibilityScanner{
 		AccessibilityScanner: scanner.NewAccessibilityScanner(baseURL),
+		Tracker:              tracker,
+		Policy:               A11yPolicyFromFile(),
+		CIMode:               os.Getenv("CI") != "",
+		ProposalsDir:         RecordingOptionsFromEnv().OutputDir,
 	}
 }
 
-// issueExists checks if an issue with the same content already exists in bd
-func issueExists(title string, selector string, url string) (bool, error) {
-	cmd := exec.Command("bd", "list", "--json")
-	output, err := cmd.Output()
-	if err != nil {
-		return false, fmt.Errorf("failed to list bd issues: %w", err)
+// AddViolations records axe results for a page alongside the library scanner's
+// findings. Pages are recorded by path, so scans of servers on different
+// ports update the same table rows.
+func (as *AccessibilityScanner) AddViolations(pageURL string, violations []AxeViolation) {
+	if u, err := url.Parse(pageURL); err == nil && u.Host != "" {
+		pageURL = u.Path
 	}
-
-	var issues []map[string]interface{}
-	if err := json.Unmarshal(output, &issues); err != nil {
-		return false, fmt.Errorf("failed to parse bd issues: %w", err)
+	as.occurrences = append(as.occurrences, OccurrencesFromViolations(pageURL, violations)...)
+	if template := PageTemplate(pageURL); !containsString(as.templates, template) {
+		as.templates = append(as.templates, template)
 	}
+}
 
-	// Check if we have an open issue with the same title
-	for _, issue := range issues {
-		if issueTitle, ok := issue["title"].(string); ok {
-			if issueTitle == title {
-				if status, ok := issue["status"].(string); ok && status != "closed" {
-					return true, nil
-				}
-			}
+// Scanned reports whether AddViolations recorded any page, even a clean one
+func (as *AccessibilityScanner) Scanned() bool {
+	return len(as.templates) > 0
+}
+
+// RunPath is where CreateBdIssuesForAll saves the run in CI, and where the
+// suite saves it after every run
+func (as *AccessibilityScanner) RunPath() string {
+	return filepath.Join(as.ProposalsDir, A11yRunFile)
+}
+
+// HasFindings reports whether the scanner has any violations to file
+func (as *AccessibilityScanner) HasFindings() bool {
+	return len(as.occurrences) > 0 || len(as.GetIssues()) > 0
+}
+
+// findIssue returns the issue tracking fingerprint. Open issues filed before
+// issues were keyed on rule and template are matched by title so they can
+// adopt it: per-selector issues by their title, and issues without a
+// fingerprint by legacyTitle.
+func findIssue(issues []Issue, fingerprint, title, legacyTitle string) *Issue {
+	if existing := FindByFingerprint(issues, fingerprint); existing != nil {
+		return existing
+	}
+	for i := range issues {
+		if !issues[i].IsOpen() {
+			continue
+		}
+		if issues[i].Title == title || (issues[i].Title == legacyTitle && FingerprintOf(issues[i]) == "") {
+			return &issues[i]
 		}
 	}
-
-	return false, nil
+	return nil
 }
 
-// CreateBdIssue creates a bd issue for accessibility problems
+// CreateBdIssue files a tracker issue for a single accessibility problem
 func (as *AccessibilityScanner) CreateBdIssue(issue scanner.AccessibilityIssue) error {
-	// Skip bd creation in CI environment
-	if os.Getenv("CI") != "" {
-		fmt.Printf("Skipping bd issue creation in CI environment for: %s\n", issue.Title)
-		return nil
+	return as.FileGroup(IssueGroups(GroupOccurrences([]A11yOccurrence{OccurrenceFromIssue(issue)}))[0])
+}
+
+// FileGroup files an issue group from IssueGroups, keyed on its fingerprint. An existing
+// open issue is updated when new pages are affected, a closed one is reopened.
+// In CI the issue is written to the proposals file instead.
+func (as *AccessibilityScanner) FileGroup(group A11yGroup) error {
+	if sources := as.sourceMap(); sources != nil && group.Sources == nil {
+		group.Sources = sources.LocateGroup(group)
 	}
 
-	// Create issue title
-	title := fmt.Sprintf("Accessibility Issue: %s", issue.Title)
+	if as.CIMode {
+		return as.propose(group)
+	}
 
-	// Check if issue already exists
-	exists, err := issueExists(title, issue.Selector, issue.URL)
+	action, err := FileProposal(as.Tracker, as.Policy.IssueFor(group))
 	if err != nil {
-		fmt.Printf("Warning: failed to check for existing issue: %v\n", err)
+		return err
+	}
+
+	switch action.Kind {
+	case ProposalCreate:
+		fmt.Printf("Created issue %s: %s (%d pages)\n", action.Issue.ID, action.Issue.Title, group.PageCount())
+	case ProposalReopen:
+		fmt.Printf("Reopened issue %s, %s regressed: %s\n", action.Issue.ID, group.Fingerprint, action.Issue.Title)
+	case ProposalAdopt:
+		fmt.Printf("Added fingerprint %s to issue %s: %s\n", group.Fingerprint, action.Issue.ID, action.Issue.Title)
+	case ProposalUpdate:
+		fmt.Printf("Updated issue %s, new pages affected: %s\n", action.Issue.ID, action.Issue.Title)
+	default:
+		fmt.Printf("Issue already exists, skipping: %s (%s)\n", action.Issue.Title, action.Issue.ID)
+	}
+	return nil
+}
+
+// sourceMap loads the source map on first use; issues are still filed
+// without sources when the site cannot be indexed
+func (as *AccessibilityScanner) sourceMap() *SourceMap {
+	if as.sources == nil && as.sourcesErr == nil {
+		as.sources, as.sourcesErr = DevelopmentSourceMap()
+		if as.sourcesErr != nil {
+			fmt.Printf("Warning: issues will not list likely sources: %v\n", as.sourcesErr)
+		}
 	}
-	if exists {
-		fmt.Printf("Issue already exists, skipping: %s\n", title)
-		return nil
+	return as.sources
+}
+
+// propose adds a group to the CI proposals and rewrites the proposal files
+func (as *AccessibilityScanner) propose(group A11yGroup) error {
+	as.proposals = append(as.proposals, as.Policy.IssueFor(group))
+	if err := as.writeProposals(); err != nil {
+		return err
 	}
+	fmt.Printf("Proposed issue in %s: %s\n", filepath.Join(as.ProposalsDir, ProposalsFile), group.IssueTitle())
+	return nil
+}
 
-	// Create issue description
-	description := fmt.Sprintf("## Accessibility Issue Found\n\nURL: %s\nImpact: %s\nSelector: %s\n\nDescription: %s\n\nTags: %s\n\nThis issue was automatically created by the Go BDD accessibility scanner.",
-		issue.URL,
-		issue.Impact,
-		issue.Selector,
-		issue.Description,
-		strings.Join(issue.Tags, ", "),
-	)
+// writeProposals writes the proposals so far with a summary against the known issues
+func (as *AccessibilityScanner) writeProposals() error {
+	return WriteProposals(as.ProposalsDir, as.proposals, as.knownIssues())
+}
 
-	// Create bd issue
-	args := []string{
-		"create", title,
-		"-t", "bug",
-		"-p", "1", // High priority for accessibility
-		"--description", description,
-		"--json",
+// knownIssues lists the tracker's issues on first use. Proposals never
+// change the tracker, so one listing serves every rewrite of the summary.
+func (as *AccessibilityScanner) knownIssues() []Issue {
+	if as.knownListed {
+		return as.known
 	}
+	as.knownListed = true
 
-	cmd := exec.Command("bd", args...)
-	output, err := cmd.Output()
+	// bd is not available in CI; the checked-in export still shows what exists
+	known, err := as.Tracker.List(IssueFilter{})
 	if err != nil {
-		return fmt.Errorf("failed to create bd issue: %w, output: %s", err, string(output))
+		known, err = NewJSONLTracker(BeadsIssuesPath).List(IssueFilter{})
+		if err != nil {
+			fmt.Printf("Warning: failed to read existing issues: %v\n", err)
+		}
 	}
+	as.known = known
+	return as.known
+}
 
-	fmt.Printf("Created bd issue: %s\n", string(output))
-	return nil
+// groups groups every finding from the library scanner and AddViolations by
+// selector, as saved in runs
+func (as *AccessibilityScanner) groups() []A11yGroup {
+	var occurrences []A11yOccurrence
+	for _, issue := range as.GetIssues() {
+		occurrences = append(occurrences, OccurrenceFromIssue(issue))
+	}
+	return GroupOccurrences(append(occurrences, as.occurrences...))
 }
 
-// CreateBdIssuesForAll creates bd issues for all found problems
+// CreateBdIssuesForAll files one tracker issue per rule and template for the
+// impacts the policy files, and prints a warning for those it only warns
+// about. In CI the run and proposal files are written even when nothing is
+// filed, so the artifacts always show the outcome.
 func (as *AccessibilityScanner) CreateBdIssuesForAll() error {
-	for _, issue := range as.GetIssues() {
-		if issue.Impact == "critical" || issue.Impact == "serious" {
-			if err := as.CreateBdIssue(issue); err != nil {
-				fmt.Printf("Failed to create issue for %s: %v\n", issue.Title, err)
+	filed := 0
+	for _, group := range IssueGroups(as.groups()) {
+		decision := as.Policy.Decide(group)
+		switch decision.Action {
+		case A11yFile:
+			filed++
+			if err := as.FileGroup(group); err != nil {
+				fmt.Printf("Failed to create issue for %s: %v\n", group.IssueTitle(), err)
 			}
+		case A11yWarn:
+			fmt.Printf("Warning: %s (%s) on %d pages, not filed: %s\n", group.IssueTitle(), group.Impact, group.PageCount(), group.Fingerprint)
+		}
+	}
+	if as.CIMode {
+		// Keep the full results next to the proposals for regression diffs
+		if _, err := as.SaveRun(as.RunPath()); err != nil {
+			return err
+		}
+		if filed == 0 {
+			return as.writeProposals()
 		}
 	}
 	return nil
 }
+
+// Run records every finding, whatever the policy does with it, as a run
+// keyed by fingerprint, with the templates scanned
+func (as *AccessibilityScanner) Run() *A11yRun {
+	groups := as.groups()
+	run := NewA11yRun(groups, time.Now())
+	run.FullSite = as.FullSite
+	run.Templates = append(run.Templates, as.templates...)
+	for _, group := range groups {
+		if !containsString(run.Templates, group.Template) {
+			run.Templates = append(run.Templates, group.Template)
+		}
+	}
+	sort.Strings(run.Templates)
+	return run
+}
+
+// SaveRun writes Run for CompareA11yRuns and reconcile-a11y-issues
+func (as *AccessibilityScanner) SaveRun(p string) (*A11yRun, error) {
+	run := as.Run()
+	if err := run.Save(p); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// ReconcileIssues closes auto-created issues the scan no longer reproduces on
+// the templates it scanned and reopens ones that regressed. With dryRun, or
+// in CI, the plan is printed and nothing is changed.
+func (as *AccessibilityScanner) ReconcileIssues(dryRun bool) (*ReconcilePlan, error) {
+	return Reconcile(as.Tracker, as.Run(), dryRun || as.CIMode)
+}
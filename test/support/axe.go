@@ -0,0 +1,156 @@
+package support
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// AxeScriptPath is the axe-core build injected into pages under test. It is a
+// devDependency of the root package.json; injecting it from disk keeps axe
+// working while the network router blocks third-party hosts.
+const AxeScriptPath = RootDir + "/node_modules/axe-core/axe.min.js"
+
+// WCAGTags are the axe tags checked by default
+var WCAGTags = []string{"wcag2a", "wcag2aa", "wcag21aa"}
+
+// AxeNode is a single element that failed an axe rule
+type AxeNode struct {
+	Target         []string `json:"target"`
+	HTML           string   `json:"html"`
+	FailureSummary string   `json:"failureSummary"`
+}
+
+// AxeViolation is an axe rule that failed on the page
+type AxeViolation struct {
+	ID          string    `json:"id"`
+	Impact      string    `json:"impact"`
+	Description string    `json:"description"`
+	Help        string    `json:"help"`
+	HelpURL     string    `json:"helpUrl"`
+	Tags        []string  `json:"tags"`
+	Nodes       []AxeNode `json:"nodes"`
+}
+
+// WCAGTagsFor returns the axe tags for conformance with a WCAG version and
+// level, which includes every earlier version and lower level, e.g. 2.1 AA
+// is wcag2a, wcag2aa, wcag21a and wcag21aa
+func WCAGTagsFor(version, level string) ([]string, error) {
+	var versions []string
+	switch version {
+	case "2.0", "2":
+		versions = []string{"2"}
+	case "2.1":
+		versions = []string{"2", "21"}
+	case "2.2":
+		versions = []string{"2", "21", "22"}
+	default:
+		return nil, fmt.Errorf("unsupported WCAG version %q", version)
+	}
+	if level != "A" && level != "AA" && level != "AAA" {
+		return nil, fmt.Errorf("unsupported WCAG level %q", level)
+	}
+
+	var tags []string
+	for _, v := range versions {
+		for l := 1; l <= len(level); l++ {
+			tags = append(tags, "wcag"+v+strings.Repeat("a", l))
+		}
+	}
+	return tags, nil
+}
+
+// InjectAxe loads axe-core into the page if it is not already present
+func InjectAxe(page playwright.Page) error {
+	loaded, err := page.Evaluate(`() => typeof axe !== 'undefined'`)
+	if err != nil {
+		return fmt.Errorf("failed to check for axe: %w", err)
+	}
+	if loaded == true {
+		return nil
+	}
+
+	if _, err := os.Stat(AxeScriptPath); err != nil {
+		return fmt.Errorf("axe-core not found at %s, run bun install in the repository root: %w", AxeScriptPath, err)
+	}
+	if _, err := page.AddScriptTag(playwright.PageAddScriptTagOptions{
+		Path: playwright.String(AxeScriptPath),
+	}); err != nil {
+		return fmt.Errorf("failed to inject axe: %w", err)
+	}
+
+	if _, err := page.WaitForFunction("() => typeof axe !== 'undefined'", nil); err != nil {
+		return fmt.Errorf("axe did not load: %w", err)
+	}
+
+	return nil
+}
+
+// RunAxeTags runs axe restricted to the given tags and returns its violations
+func RunAxeTags(page playwright.Page, tags []string) ([]AxeViolation, error) {
+	return runAxe(page, map[string]interface{}{"type": "tag", "values": tags})
+}
+
+// RunAxeRules runs only the given axe rules and returns their violations
+func RunAxeRules(page playwright.Page, rules []string) ([]AxeViolation, error) {
+	return runAxe(page, map[string]interface{}{"type": "rule", "values": rules})
+}
+
+// RunAxeWithWaivers runs axe restricted to tags and removes the violations
+// waived in a11y-waivers.toml
+func RunAxeWithWaivers(page playwright.Page, tags []string) (A11yResult, error) {
+	waivers, err := LoadA11yWaivers(A11yWaiversPath)
+	if err != nil {
+		return A11yResult{}, err
+	}
+	violations, err := RunAxeTags(page, tags)
+	if err != nil {
+		return A11yResult{}, err
+	}
+	return waivers.Apply(page.URL(), violations, time.Now()), nil
+}
+
+// runAxe injects axe, runs it with the runOnly filter and decodes the violations
+func runAxe(page playwright.Page, runOnly map[string]interface{}) ([]AxeViolation, error) {
+	if err := InjectAxe(page); err != nil {
+		return nil, err
+	}
+
+	result, err := page.Evaluate(`(runOnly) => axe.run(document, {
+		reporter: 'v2',
+		runOnly: runOnly
+	}).then(results => results.violations)`, runOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run axe: %w", err)
+	}
+
+	return decodeViolations(result)
+}
+
+// decodeViolations converts the evaluated axe result into typed violations
+func decodeViolations(result interface{}) ([]AxeViolation, error) {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode axe result: %w", err)
+	}
+
+	var violations []AxeViolation
+	if err := json.Unmarshal(raw, &violations); err != nil {
+		return nil, fmt.Errorf("failed to parse axe violations: %w", err)
+	}
+
+	return violations, nil
+}
+
+// SummarizeViolations renders one line per violated rule for failure messages
+func SummarizeViolations(violations []AxeViolation) string {
+	summary := ""
+	for _, v := range violations {
+		summary += fmt.Sprintf("\n  - %s (%s): %s [%d nodes]", v.ID, v.Impact, v.Help, len(v.Nodes))
+	}
+	return summary
+}
@@ -0,0 +1,49 @@
+package support
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodeViolations tests decoding the evaluated axe violations
+func TestDecodeViolations(t *testing.T) {
+	// Shape returned by page.Evaluate for axe's violations array
+	result := []interface{}{
+		map[string]interface{}{
+			"id":          "color-contrast",
+			"impact":      "serious",
+			"description": "Ensures the contrast between foreground and background colors meets WCAG 2 AA",
+			"help":        "Elements must have sufficient color contrast",
+			"helpUrl":     "https://dequeuniversity.com/rules/axe/4.8/color-contrast",
+			"tags":        []interface{}{"wcag2aa", "wcag143"},
+			"nodes": []interface{}{
+				map[string]interface{}{
+					"target":         []interface{}{".card-tools > p"},
+					"html":           "<p class=\"text-base-content/50\">",
+					"failureSummary": "Fix any of the following",
+				},
+			},
+		},
+	}
+
+	violations, err := decodeViolations(result)
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "color-contrast", violations[0].ID)
+	assert.Equal(t, "serious", violations[0].Impact)
+	assert.Equal(t, []string{"wcag2aa", "wcag143"}, violations[0].Tags)
+	require.Len(t, violations[0].Nodes, 1)
+	assert.Equal(t, []string{".card-tools > p"}, violations[0].Nodes[0].Target)
+
+	assert.Contains(t, SummarizeViolations(violations), "color-contrast (serious)")
+}
+
+// TestDecodeViolations_Empty tests a clean scan
+func TestDecodeViolations_Empty(t *testing.T) {
+	violations, err := decodeViolations([]interface{}{})
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+	assert.Empty(t, SummarizeViolations(violations))
+}
@@ -0,0 +1,120 @@
+package support
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// BdTracker files issues through the bd CLI
+type BdTracker struct {
+	// Run executes bd with args and returns its stdout; replaceable in tests
+	Run func(args ...string) ([]byte, error)
+}
+
+// NewBdTracker creates a tracker that shells out to bd on the PATH
+func NewBdTracker() *BdTracker {
+	return &BdTracker{Run: runBd}
+}
+
+// runBd executes the bd CLI, including stderr in failures
+func runBd(args ...string) ([]byte, error) {
+	output, err := exec.Command("bd", args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return output, fmt.Errorf("bd %s: %w: %s", args[0], err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return output, fmt.Errorf("bd %s: %w", args[0], err)
+	}
+	return output, nil
+}
+
+// List runs bd list --json, narrowed by --status when filtered. Labels are
+// matched here rather than with a bd flag.
+func (t *BdTracker) List(filter IssueFilter) ([]Issue, error) {
+	args := []string{"list", "--json"}
+	if filter.Status != "" {
+		args = append(args, "--status", filter.Status)
+	}
+
+	output, err := t.Run(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bd issues: %w", err)
+	}
+
+	var listed []Issue
+	if err := json.Unmarshal(output, &listed); err != nil {
+		return nil, fmt.Errorf("failed to parse bd issues: %w", err)
+	}
+	var issues []Issue
+	for _, issue := range listed {
+		if filter.Matches(issue) {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// labelArgs repeats --label for each label, as bd create is used elsewhere
+// in the repository (packages/agents/src/task-create/README.md)
+func labelArgs(labels []string) []string {
+	args := make([]string, 0, 2*len(labels))
+	for _, label := range labels {
+		args = append(args, "--label", label)
+	}
+	return args
+}
+
+// Create runs bd create and returns the issue bd reports
+func (t *BdTracker) Create(issue Issue) (Issue, error) {
+	args := []string{
+		"create", issue.Title,
+		"-t", issue.IssueType,
+		"-p", strconv.Itoa(issue.Priority),
+		"--description", issue.Description,
+		"--json",
+	}
+	args = append(args, labelArgs(issue.Labels)...)
+
+	output, err := t.Run(args...)
+	if err != nil {
+		return Issue{}, fmt.Errorf("failed to create bd issue: %w, output: %s", err, string(output))
+	}
+
+	var created Issue
+	if err := json.Unmarshal(output, &created); err != nil {
+		return Issue{}, fmt.Errorf("failed to parse created bd issue: %w", err)
+	}
+	return created, nil
+}
+
+// Update runs bd update with the issue's editable fields and its labels in
+// the same repeated --label form as Create
+func (t *BdTracker) Update(issue Issue) error {
+	args := []string{
+		"update", issue.ID,
+		"--title", issue.Title,
+		"--description", issue.Description,
+		"--priority", strconv.Itoa(issue.Priority),
+	}
+	args = append(args, labelArgs(issue.Labels)...)
+	if issue.Status != "" {
+		args = append(args, "--status", issue.Status)
+	}
+	args = append(args, "--json")
+
+	if output, err := t.Run(args...); err != nil {
+		return fmt.Errorf("failed to update bd issue %s: %w, output: %s", issue.ID, err, string(output))
+	}
+	return nil
+}
+
+// Close runs bd close with the reason
+func (t *BdTracker) Close(id, reason string) error {
+	if output, err := t.Run("close", id, "--reason", reason, "--json"); err != nil {
+		return fmt.Errorf("failed to close bd issue %s: %w, output: %s", id, err, string(output))
+	}
+	return nil
+}
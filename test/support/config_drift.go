@@ -0,0 +1,293 @@
+package support
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ConfigDriftPolicyPath lists the config keys environments may set differently
+const ConfigDriftPolicyPath = "config-drift-policy.toml"
+
+// configFileExtensions are the formats Hugo reads from a config directory
+var configFileExtensions = map[string]bool{".toml": true, ".yaml": true, ".yml": true, ".json": true}
+
+// LoadMergedConfig returns the site config with an environment's config
+// merged over it, the way hugo --environment layers them
+func LoadMergedConfig(environment string) (map[string]interface{}, error) {
+	merged, err := decodeConfig(SiteConfigPath())
+	if err != nil {
+		return nil, err
+	}
+	envConfig, err := decodeConfig(EnvironmentConfigPath(environment))
+	if err != nil {
+		return nil, err
+	}
+	mergeConfig(merged, envConfig)
+	return merged, nil
+}
+
+// decodeConfig reads a TOML config file into a generic map
+func decodeConfig(p string) (map[string]interface{}, error) {
+	config := make(map[string]interface{})
+	if _, err := toml.DecodeFile(p, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", p, err)
+	}
+	return config, nil
+}
+
+// mergeConfig merges src into dst; tables merge key by key, anything else
+// in src replaces the value in dst
+func mergeConfig(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcTable, srcIsTable := value.(map[string]interface{})
+		dstTable, dstIsTable := dst[key].(map[string]interface{})
+		if srcIsTable && dstIsTable {
+			mergeConfig(dstTable, srcTable)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// FlattenConfig returns the leaf values of a config keyed by dotted path,
+// e.g. imaging.resizing.blog.quality. Arrays are leaves.
+func FlattenConfig(config map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{})
+	var walk func(prefix string, table map[string]interface{})
+	walk = func(prefix string, table map[string]interface{}) {
+		for key, value := range table {
+			if nested, ok := value.(map[string]interface{}); ok {
+				walk(prefix+key+".", nested)
+				continue
+			}
+			flat[prefix+key] = value
+		}
+	}
+	walk("", config)
+	return flat
+}
+
+// ConfigDifference is a config key whose value is not the same in every environment
+type ConfigDifference struct {
+	Key    string
+	Values map[string]interface{} // environment -> value; absent if unset
+	// Allowed and Reason are filled in by ConfigDriftPolicy.Apply
+	Allowed bool
+	Reason  string
+}
+
+// String formats the difference as "key: env=value, ..." in environment order
+func (d ConfigDifference) String() string {
+	parts := make([]string, 0, len(d.Values))
+	for _, env := range d.environments() {
+		parts = append(parts, env+"="+formatConfigValue(d.Values[env]))
+	}
+	return d.Key + ": " + strings.Join(parts, ", ")
+}
+
+// environments returns the environments compared, sorted
+func (d ConfigDifference) environments() []string {
+	envs := make([]string, 0, len(d.Values))
+	for env := range d.Values {
+		envs = append(envs, env)
+	}
+	sort.Strings(envs)
+	return envs
+}
+
+// unsetConfigValue marks a key an environment does not set
+type unsetConfigValue struct{}
+
+// formatConfigValue renders a config value the way it would appear in TOML
+func formatConfigValue(value interface{}) string {
+	switch v := value.(type) {
+	case unsetConfigValue:
+		return "<unset>"
+	case string:
+		return fmt.Sprintf("%q", v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// DiffConfigs compares flattened configs by environment and returns the keys
+// whose values differ, sorted by key. Unset keys count as a difference.
+func DiffConfigs(configs map[string]map[string]interface{}) []ConfigDifference {
+	keys := make(map[string]bool)
+	for _, flat := range configs {
+		for key := range flat {
+			keys[key] = true
+		}
+	}
+
+	var diffs []ConfigDifference
+	for key := range keys {
+		values := make(map[string]interface{}, len(configs))
+		for env, flat := range configs {
+			value, ok := flat[key]
+			if !ok {
+				value = unsetConfigValue{}
+			}
+			values[env] = value
+		}
+		if !allEqual(values) {
+			diffs = append(diffs, ConfigDifference{Key: key, Values: values})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Key < diffs[j].Key })
+	return diffs
+}
+
+// allEqual reports whether every value in the map is deeply equal
+func allEqual(values map[string]interface{}) bool {
+	var first interface{}
+	seen := false
+	for _, value := range values {
+		if !seen {
+			first, seen = value, true
+			continue
+		}
+		if !reflect.DeepEqual(first, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ConfigDriftRule is one [[allow]] entry in the drift policy
+type ConfigDriftRule struct {
+	Key string `toml:"key"` // dotted key, may use path.Match wildcards
+	// Environments that may deviate; the others must still agree. Empty means any.
+	Environments []string `toml:"environments"`
+	Reason       string   `toml:"reason"`
+}
+
+// Matches reports whether the rule covers a dotted config key
+func (r ConfigDriftRule) Matches(key string) bool {
+	ok, _ := path.Match(r.Key, key)
+	return ok
+}
+
+// permits reports whether the rule allows a difference: every environment
+// not listed in the rule must have the same value
+func (r ConfigDriftRule) permits(diff ConfigDifference) bool {
+	if len(r.Environments) == 0 {
+		return true
+	}
+	rest := make(map[string]interface{})
+	for env, value := range diff.Values {
+		if !containsString(r.Environments, env) {
+			rest[env] = value
+		}
+	}
+	return allEqual(rest)
+}
+
+// ConfigDriftPolicy mirrors config-drift-policy.toml
+type ConfigDriftPolicy struct {
+	Allow []ConfigDriftRule `toml:"allow"`
+}
+
+// LoadConfigDriftPolicy reads and validates a drift policy file
+func LoadConfigDriftPolicy(p string) (*ConfigDriftPolicy, error) {
+	var policy ConfigDriftPolicy
+	if _, err := toml.DecodeFile(p, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse drift policy %s: %w", p, err)
+	}
+
+	for i, rule := range policy.Allow {
+		if rule.Key == "" {
+			return nil, fmt.Errorf("drift policy %s: allow rule %d has no key", p, i+1)
+		}
+		if _, err := path.Match(rule.Key, ""); err != nil {
+			return nil, fmt.Errorf("drift policy %s: invalid key pattern %q: %w", p, rule.Key, err)
+		}
+		if strings.TrimSpace(rule.Reason) == "" {
+			return nil, fmt.Errorf("drift policy %s: allow rule for %q needs a reason", p, rule.Key)
+		}
+	}
+	return &policy, nil
+}
+
+// Apply marks the differences the policy allows and returns the unexpected ones
+func (p *ConfigDriftPolicy) Apply(diffs []ConfigDifference) []ConfigDifference {
+	var unexpected []ConfigDifference
+	for i := range diffs {
+		for _, rule := range p.Allow {
+			if rule.Matches(diffs[i].Key) && rule.permits(diffs[i]) {
+				diffs[i].Allowed = true
+				diffs[i].Reason = rule.Reason
+				break
+			}
+		}
+		if !diffs[i].Allowed {
+			unexpected = append(unexpected, diffs[i])
+		}
+	}
+	return unexpected
+}
+
+// EnvironmentConfigDrift merges and diffs the config of every configured environment
+func EnvironmentConfigDrift() ([]ConfigDifference, error) {
+	environments, err := ConfiguredEnvironments()
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make(map[string]map[string]interface{}, len(environments))
+	for _, env := range environments {
+		merged, err := LoadMergedConfig(env)
+		if err != nil {
+			return nil, err
+		}
+		configs[env] = FlattenConfig(merged)
+	}
+	return DiffConfigs(configs), nil
+}
+
+// FormatConfigDrift renders differences as a report, marking allowed ones
+// with ~ and their reason, and unexpected ones with !
+func FormatConfigDrift(diffs []ConfigDifference) string {
+	var b strings.Builder
+	for _, diff := range diffs {
+		if diff.Allowed {
+			fmt.Fprintf(&b, "  ~ %s (%s)\n", diff, diff.Reason)
+		} else {
+			fmt.Fprintf(&b, "  ! %s\n", diff)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// StrayConfigFiles lists files in config/<env>/ that Hugo ignores, such as
+// editor backups, relative to the repository root
+func StrayConfigFiles() ([]string, error) {
+	environments, err := ConfiguredEnvironments()
+	if err != nil {
+		return nil, err
+	}
+
+	var stray []string
+	for _, env := range environments {
+		dir := filepath.Join(RootDir, "config", env)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || configFileExtensions[filepath.Ext(entry.Name())] {
+				continue
+			}
+			stray = append(stray, path.Join("config", env, entry.Name()))
+		}
+	}
+	return stray, nil
+}
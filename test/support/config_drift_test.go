@@ -0,0 +1,91 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMergeConfig tests environment tables merge into the site config key by key
+func TestMergeConfig(t *testing.T) {
+	site := map[string]interface{}{
+		"baseURL": "https://example.com",
+		"imaging": map[string]interface{}{"quality": int64(85), "anchor": "smart"},
+		"outputs": map[string]interface{}{"home": []interface{}{"HTML", "RSS"}},
+	}
+	env := map[string]interface{}{
+		"baseURL": "http://localhost:1313",
+		"imaging": map[string]interface{}{"quality": int64(80)},
+		"outputs": map[string]interface{}{"home": []interface{}{"HTML"}},
+	}
+
+	mergeConfig(site, env)
+	flat := FlattenConfig(site)
+
+	assert.Equal(t, "http://localhost:1313", flat["baseURL"])
+	assert.Equal(t, int64(80), flat["imaging.quality"])
+	assert.Equal(t, "smart", flat["imaging.anchor"])
+	assert.Equal(t, []interface{}{"HTML"}, flat["outputs.home"])
+}
+
+// TestDiffConfigs tests only differing keys are reported, including unset ones
+func TestDiffConfigs(t *testing.T) {
+	diffs := DiffConfigs(map[string]map[string]interface{}{
+		"production": {"baseURL": "https://example.com", "imaging.quality": int64(85), "title": "Site"},
+		"staging":    {"baseURL": "https://staging.example.com", "imaging.quality": int64(80), "title": "Site", "params.showBeta": true},
+	})
+
+	require.Len(t, diffs, 3)
+	assert.Equal(t, "baseURL", diffs[0].Key)
+	assert.Equal(t, `imaging.quality: production=85, staging=80`, diffs[1].String())
+	assert.Equal(t, `params.showBeta: production=<unset>, staging=true`, diffs[2].String())
+}
+
+// TestConfigDriftPolicy_Apply tests wildcards and environment restrictions
+func TestConfigDriftPolicy_Apply(t *testing.T) {
+	policy := &ConfigDriftPolicy{Allow: []ConfigDriftRule{
+		{Key: "baseURL", Reason: "own host"},
+		{Key: "build[DFE]*", Environments: []string{"development"}, Reason: "previews"},
+	}}
+	diffs := DiffConfigs(map[string]map[string]interface{}{
+		"development": {"baseURL": "http://localhost:1313", "buildDrafts": true, "imaging.quality": int64(85)},
+		"staging":     {"baseURL": "https://staging.example.com", "buildDrafts": true, "imaging.quality": int64(80)},
+		"production":  {"baseURL": "https://example.com", "imaging.quality": int64(85)},
+	})
+
+	unexpected := policy.Apply(diffs)
+
+	keys := make([]string, 0, len(unexpected))
+	for _, diff := range unexpected {
+		keys = append(keys, diff.Key)
+	}
+	// buildDrafts is only allowed to deviate in development, not staging
+	assert.Equal(t, []string{"buildDrafts", "imaging.quality"}, keys)
+	assert.True(t, diffs[0].Allowed)
+	assert.Equal(t, "own host", diffs[0].Reason)
+	assert.Contains(t, FormatConfigDrift(diffs), "  ! imaging.quality: development=85, production=85, staging=80")
+}
+
+// TestLoadConfigDriftPolicy_RequiresReason tests every allowed difference is justified
+func TestLoadConfigDriftPolicy_RequiresReason(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.toml")
+	require.NoError(t, os.WriteFile(path, []byte("[[allow]]\nkey = \"baseURL\"\n"), 0o644))
+
+	_, err := LoadConfigDriftPolicy(path)
+	assert.ErrorContains(t, err, `allow rule for "baseURL" needs a reason`)
+}
+
+// TestEnvironmentConfigDrift tests the repository's environments satisfy the policy
+func TestEnvironmentConfigDrift(t *testing.T) {
+	t.Chdir("..")
+
+	diffs, err := EnvironmentConfigDrift()
+	require.NoError(t, err)
+	policy, err := LoadConfigDriftPolicy(ConfigDriftPolicyPath)
+	require.NoError(t, err)
+
+	assert.Empty(t, policy.Apply(diffs), FormatConfigDrift(diffs))
+}
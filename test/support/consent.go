@@ -0,0 +1,46 @@
+package support
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// CookieConsentStorageKey is the localStorage key written by window.CookieConsent
+const CookieConsentStorageKey = "cookie-consent"
+
+// CookieConsentBanner is the banner rendered by partials/components/cookie-consent.html
+const CookieConsentBanner = "#cookie-consent-banner"
+
+// AnalyticsURLPattern matches Google Tag Manager and Google Analytics requests,
+// which the network router stubs with a local fixture
+var AnalyticsURLPattern = regexp.MustCompile(`^https?://([a-z0-9-]+\.)*(googletagmanager|google-analytics)\.com/`)
+
+// CookieConsent is the consent record stored by the banner
+type CookieConsent struct {
+	Essential bool   `json:"essential"`
+	Analytics bool   `json:"analytics"`
+	Marketing bool   `json:"marketing"`
+	Timestamp string `json:"timestamp"`
+}
+
+// StoredConsent returns the consent record in localStorage, or nil if none was made
+func StoredConsent(page playwright.Page) (*CookieConsent, error) {
+	raw, err := page.Evaluate(`(key) => localStorage.getItem(key)`, CookieConsentStorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not read stored consent: %w", err)
+	}
+
+	value, ok := raw.(string)
+	if !ok || value == "" {
+		return nil, nil
+	}
+
+	var consent CookieConsent
+	if err := json.Unmarshal([]byte(value), &consent); err != nil {
+		return nil, fmt.Errorf("stored consent is not valid JSON: %w", err)
+	}
+	return &consent, nil
+}
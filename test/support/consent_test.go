@@ -0,0 +1,29 @@
+package support
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAnalyticsURLPattern tests which hosts count as analytics
+func TestAnalyticsURLPattern(t *testing.T) {
+	tests := []struct {
+		url   string
+		match bool
+	}{
+		{"https://www.googletagmanager.com/gtm.js?id=GTM-N9CR6KJ5", true},
+		{"https://googletagmanager.com/gtag/js", true},
+		{"https://www.google-analytics.com/g/collect?v=2", true},
+		{"https://region1.google-analytics.com/g/collect", true},
+		{"https://www.google.com/search", false},
+		{"https://example.com/?ref=googletagmanager.com/", false},
+		{"http://localhost:1313/js/analytics.js", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.match, AnalyticsURLPattern.MatchString(tt.url))
+		})
+	}
+}
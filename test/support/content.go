@@ -0,0 +1,246 @@
+package support
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ContentDir is the site's Markdown content
+const ContentDir = SiteDir + "/content"
+
+// ContentItem is a content file and the frontmatter that decides whether Hugo publishes it
+type ContentItem struct {
+	Path        string // relative to the content directory, slash separated
+	Title       string
+	Slug        string
+	URL         string // explicit url frontmatter
+	Draft       bool
+	Date        time.Time
+	PublishDate time.Time
+	ExpiryDate  time.Time
+}
+
+// LoadContent reads the frontmatter of every Markdown file under dir
+func LoadContent(dir string) ([]ContentItem, error) {
+	var items []ContentItem
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(p) != ".md" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		item, err := LoadContentItem(p)
+		if err != nil {
+			return err
+		}
+		item.Path = filepath.ToSlash(rel)
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load content from %s: %w", dir, err)
+	}
+	return items, nil
+}
+
+// LoadContentItem parses one content file's YAML (---) or TOML (+++) frontmatter
+func LoadContentItem(p string) (ContentItem, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return ContentItem{}, err
+	}
+
+	params, err := parseFrontmatter(data)
+	if err != nil {
+		return ContentItem{}, fmt.Errorf("invalid frontmatter in %s: %w", p, err)
+	}
+
+	item := ContentItem{
+		Title: stringParam(params, "title"),
+		Slug:  stringParam(params, "slug"),
+		URL:   stringParam(params, "url"),
+	}
+	item.Draft, _ = params["draft"].(bool)
+	for key, target := range map[string]*time.Time{
+		"date":        &item.Date,
+		"publishdate": &item.PublishDate,
+		"expirydate":  &item.ExpiryDate,
+	} {
+		if *target, err = timeParam(params, key); err != nil {
+			return ContentItem{}, fmt.Errorf("invalid %s in %s: %w", key, p, err)
+		}
+	}
+
+	return item, nil
+}
+
+// parseFrontmatter decodes the frontmatter block into lower-cased keys
+func parseFrontmatter(data []byte) (map[string]interface{}, error) {
+	raw := make(map[string]interface{})
+
+	for _, format := range []struct {
+		delim  string
+		decode func([]byte, interface{}) error
+	}{
+		{"---", yaml.Unmarshal},
+		{"+++", toml.Unmarshal},
+	} {
+		if !bytes.HasPrefix(data, []byte(format.delim)) {
+			continue
+		}
+		rest := data[len(format.delim):]
+		end := bytes.Index(rest, []byte("\n"+format.delim))
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated %s frontmatter", format.delim)
+		}
+		if err := format.decode(rest[:end], &raw); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	params := make(map[string]interface{}, len(raw))
+	for key, value := range raw {
+		params[strings.ToLower(key)] = value
+	}
+	return params, nil
+}
+
+// stringParam returns a string frontmatter value, or ""
+func stringParam(params map[string]interface{}, key string) string {
+	value, _ := params[key].(string)
+	return value
+}
+
+// frontmatterDateLayouts are the date formats Hugo accepts in frontmatter
+var frontmatterDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// timeParam returns a date frontmatter value, or the zero time if unset
+func timeParam(params map[string]interface{}, key string) (time.Time, error) {
+	switch value := params[key].(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return value, nil
+	case string:
+		if value == "" {
+			return time.Time{}, nil
+		}
+		for _, layout := range frontmatterDateLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("unrecognized date %q", value)
+	default:
+		return time.Time{}, fmt.Errorf("unexpected %T", value)
+	}
+}
+
+// UnpublishedReason explains why Hugo would not publish the item in an
+// environment without buildDrafts/buildFuture/buildExpired, or "" if it would
+func (c ContentItem) UnpublishedReason(now time.Time) string {
+	publish := c.PublishDate
+	if publish.IsZero() {
+		publish = c.Date
+	}
+
+	switch {
+	case c.Draft:
+		return "draft"
+	case !publish.IsZero() && publish.After(now):
+		return "future-dated " + publish.Format("2006-01-02")
+	case !c.ExpiryDate.IsZero() && !c.ExpiryDate.After(now):
+		return "expired " + c.ExpiryDate.Format("2006-01-02")
+	}
+	return ""
+}
+
+// PublishOptions are a build's buildDrafts, buildFuture and buildExpired settings
+type PublishOptions struct {
+	Drafts  bool
+	Future  bool
+	Expired bool
+}
+
+// EnvironmentPublishOptions reads the publish settings of an environment's merged config
+func EnvironmentPublishOptions(environment string) (PublishOptions, error) {
+	config, err := LoadMergedConfig(environment)
+	if err != nil {
+		return PublishOptions{}, err
+	}
+	drafts, _ := config["buildDrafts"].(bool)
+	future, _ := config["buildFuture"].(bool)
+	expired, _ := config["buildExpired"].(bool)
+	return PublishOptions{Drafts: drafts, Future: future, Expired: expired}, nil
+}
+
+// PublishedWith reports whether Hugo renders the item in a build with opts
+func (c ContentItem) PublishedWith(now time.Time, opts PublishOptions) bool {
+	switch reason := c.UnpublishedReason(now); {
+	case reason == "":
+		return true
+	case reason == "draft":
+		return opts.Drafts
+	case strings.HasPrefix(reason, "future"):
+		return opts.Future
+	default:
+		return opts.Expired
+	}
+}
+
+// IsSection reports whether the file is a section's _index.md
+func (c ContentItem) IsSection() bool {
+	return path.Base(c.Path) == "_index.md"
+}
+
+// URLPath returns the site-relative URL Hugo renders the item at, e.g.
+// tools/bun/index.md and blog/post.md become /tools/bun/ and /blog/post/
+func (c ContentItem) URLPath() string {
+	if c.URL != "" {
+		return ensureTrailingSlash("/" + strings.TrimPrefix(c.URL, "/"))
+	}
+
+	dir, file := path.Split(c.Path)
+	name := strings.TrimSuffix(file, path.Ext(file))
+	if name == "index" || name == "_index" {
+		// Bundles take their name from the directory
+		dir = strings.TrimSuffix(dir, "/")
+		dir, name = path.Split(dir)
+	}
+	if c.Slug != "" {
+		name = c.Slug
+	}
+
+	// Hugo lower-cases paths and turns spaces into hyphens
+	urlPath := strings.ToLower(strings.ReplaceAll("/"+dir+name, " ", "-"))
+	return ensureTrailingSlash(urlPath)
+}
+
+// ensureTrailingSlash normalizes a URL path to end in /
+func ensureTrailingSlash(p string) string {
+	if !strings.HasSuffix(p, "/") {
+		return p + "/"
+	}
+	return p
+}
@@ -0,0 +1,181 @@
+package support
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RadarOutputPath is the tech radar data rendered from layouts/tools/radar.json
+const RadarOutputPath = "tools/radar.json"
+
+// OutputIndex records which URLs and titles each generated output lists
+type OutputIndex struct {
+	urls   map[string][]string // URL path -> outputs listing it
+	titles map[string][]string // radar entry name -> outputs listing it
+}
+
+// IndexBuildOutputs collects the URLs in sitemaps, feeds and JSON outputs
+// and the entry names in the tech radar
+func IndexBuildOutputs(site *BuiltSite) (*OutputIndex, error) {
+	index := &OutputIndex{
+		urls:   make(map[string][]string),
+		titles: make(map[string][]string),
+	}
+	siteHost := hostOf(site.BaseURL)
+
+	err := filepath.WalkDir(site.Dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(p)
+		if d.IsDir() || (ext != ".xml" && ext != ".json") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(site.Dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case rel == RadarOutputPath:
+			return index.addRadar(rel, data)
+		case ext == ".xml":
+			for _, m := range xmlCanonicalPattern.FindAllStringSubmatch(string(data), -1) {
+				index.addURL(m[2], siteHost, rel)
+			}
+		case ext == ".json":
+			var doc interface{}
+			if err := json.Unmarshal(data, &doc); err != nil {
+				return fmt.Errorf("%s is not valid JSON: %w", rel, err)
+			}
+			walkJSONStrings(doc, func(value string) {
+				index.addURL(value, siteHost, rel)
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to index outputs in %s: %w", site.Dir, err)
+	}
+
+	return index, nil
+}
+
+// addURL records a site URL found in an output; other values are ignored
+func (idx *OutputIndex) addURL(raw, siteHost, output string) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return
+	}
+	if u.Host != "" && !strings.EqualFold(u.Hostname(), siteHost) {
+		return
+	}
+	if u.Host == "" && !strings.HasPrefix(u.Path, "/") {
+		return
+	}
+
+	p := u.Path
+	if path.Ext(p) == "" {
+		p = ensureTrailingSlash(p)
+	}
+	if !containsString(idx.urls[p], output) {
+		idx.urls[p] = append(idx.urls[p], output)
+	}
+}
+
+// addRadar records the entry names listed in the tech radar
+func (idx *OutputIndex) addRadar(output string, data []byte) error {
+	var entries []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("%s is not a radar entry list: %w", output, err)
+	}
+	for _, entry := range entries {
+		idx.titles[entry.Name] = append(idx.titles[entry.Name], output)
+	}
+	return nil
+}
+
+// walkJSONStrings calls fn for every string value in a decoded JSON document
+func walkJSONStrings(value interface{}, fn func(string)) {
+	switch v := value.(type) {
+	case string:
+		fn(v)
+	case []interface{}:
+		for _, item := range v {
+			walkJSONStrings(item, fn)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			walkJSONStrings(item, fn)
+		}
+	}
+}
+
+// ContentLeak is unpublished content that made it into a build
+type ContentLeak struct {
+	Item    ContentItem
+	Reason  string
+	Outputs []string
+}
+
+// String formats the leak for failure messages
+func (l ContentLeak) String() string {
+	return fmt.Sprintf("%s (%s, %s) appears in %s",
+		l.Item.Path, l.Reason, l.Item.URLPath(), strings.Join(l.Outputs, ", "))
+}
+
+// FindContentLeaks returns items the environment does not publish that have
+// an HTML page or are listed in any sitemap, feed, JSON index or the tech radar
+func FindContentLeaks(site *BuiltSite, items []ContentItem, now time.Time) ([]ContentLeak, error) {
+	opts, err := EnvironmentPublishOptions(site.Environment)
+	if err != nil {
+		return nil, err
+	}
+	index, err := IndexBuildOutputs(site)
+	if err != nil {
+		return nil, err
+	}
+
+	var leaks []ContentLeak
+	for _, item := range items {
+		if item.PublishedWith(now, opts) {
+			continue
+		}
+		reason := item.UnpublishedReason(now)
+
+		urlPath := item.URLPath()
+		outputs := append([]string(nil), index.urls[urlPath]...)
+
+		page := filepath.Join(site.Dir, filepath.FromSlash(strings.TrimPrefix(urlPath, "/")), "index.html")
+		if _, err := os.Stat(page); err == nil {
+			outputs = append(outputs, strings.TrimPrefix(urlPath, "/")+"index.html")
+		}
+		if item.Title != "" {
+			outputs = append(outputs, index.titles[item.Title]...)
+		}
+
+		if len(outputs) > 0 {
+			sort.Strings(outputs)
+			leaks = append(leaks, ContentLeak{Item: item, Reason: reason, Outputs: outputs})
+		}
+	}
+
+	return leaks, nil
+}
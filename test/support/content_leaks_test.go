@@ -0,0 +1,77 @@
+package support
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFindContentLeaks tests unpublished items are found in every output format
+func TestFindContentLeaks(t *testing.T) {
+	t.Chdir("..")
+	dir := t.TempDir()
+	writeContent(t, dir, "sitemap.xml", `<urlset><url><loc>https://peterwarnock.com/blog/draft-post/</loc></url>`+
+		`<url><loc>https://peterwarnock.com/blog/live/</loc></url></urlset>`)
+	writeContent(t, dir, "blog/index.xml", `<rss><channel><item><link>https://peterwarnock.com/blog/draft-feed/</link></item></channel></rss>`)
+	writeContent(t, dir, "index.json", `{"pages":[{"url":"https://peterwarnock.com/blog/expired/"},{"url":"https://other.example.com/blog/draft-post/"}]}`)
+	writeContent(t, dir, "tools/radar.json", `[{"name":"Bun","ring":"trial"}]`)
+	writeContent(t, dir, "tools/deno/index.html", `<html></html>`)
+
+	site := &BuiltSite{Dir: dir, Environment: "production", BaseURL: "https://peterwarnock.com"}
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	past := now.AddDate(0, -1, 0)
+
+	items := []ContentItem{
+		{Path: "blog/draft-post.md", Draft: true},
+		{Path: "blog/live.md", Date: past},
+		{Path: "blog/draft-feed.md", Draft: true},
+		{Path: "blog/expired.md", Date: past, ExpiryDate: past},
+		{Path: "tools/bun/index.md", Title: "Bun", Draft: true},
+		{Path: "tools/deno/index.md", Title: "Deno", Draft: true},
+		{Path: "blog/hidden.md", Draft: true},
+	}
+
+	leaks, err := FindContentLeaks(site, items, now)
+	require.NoError(t, err)
+
+	got := make(map[string][]string)
+	for _, leak := range leaks {
+		got[leak.Item.Path] = leak.Outputs
+	}
+	assert.Equal(t, map[string][]string{
+		"blog/draft-post.md":  {"sitemap.xml"},
+		"blog/draft-feed.md":  {"blog/index.xml"},
+		"blog/expired.md":     {"index.json"},
+		"tools/bun/index.md":  {"tools/radar.json"},
+		"tools/deno/index.md": {"tools/deno/index.html"},
+	}, got)
+}
+
+// TestFindContentLeaks_BuildFuture tests future-dated items are not leaks
+// when the environment builds future content
+func TestFindContentLeaks_BuildFuture(t *testing.T) {
+	t.Chdir("..")
+	dir := t.TempDir()
+	writeContent(t, dir, "sitemap.xml", `<urlset><url><loc>https://peterwarnock.com/blog/scheduled/</loc></url></urlset>`)
+	writeContent(t, dir, "blog/scheduled/index.html", `<html></html>`)
+
+	// hugo.toml sets buildFuture = true and production does not override it
+	site := &BuiltSite{Dir: dir, Environment: "production", BaseURL: "https://peterwarnock.com"}
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	items := []ContentItem{{Path: "blog/scheduled.md", Date: now.AddDate(0, 1, 0)}}
+
+	leaks, err := FindContentLeaks(site, items, now)
+	require.NoError(t, err)
+	assert.Empty(t, leaks)
+}
+
+// TestIndexBuildOutputs_InvalidJSON tests broken JSON outputs are reported
+func TestIndexBuildOutputs_InvalidJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeContent(t, dir, "index.json", `{"pages": [,]}`)
+
+	_, err := IndexBuildOutputs(&BuiltSite{Dir: dir, BaseURL: "https://peterwarnock.com"})
+	assert.ErrorContains(t, err, "index.json is not valid JSON")
+}
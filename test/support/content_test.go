@@ -0,0 +1,92 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeContent creates a content file under dir
+func writeContent(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+// TestLoadContent tests YAML and TOML frontmatter parsing
+func TestLoadContent(t *testing.T) {
+	dir := t.TempDir()
+	writeContent(t, dir, "tools/bun/index.md", "---\ntitle: Bun\ndate: \"2025-12-27\"\ndraft: true\n---\n# Bun\n")
+	writeContent(t, dir, "blog/post.md", "+++\ntitle = \"Post\"\ndate = 2025-01-02T10:00:00Z\nexpiryDate = \"2025-06-01\"\nslug = \"My Post\"\n+++\nBody\n")
+	writeContent(t, dir, "about.md", "No frontmatter\n")
+	writeContent(t, dir, "notes.txt", "---\ntitle: ignored\n---\n")
+
+	items, err := LoadContent(dir)
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	byPath := make(map[string]ContentItem)
+	for _, item := range items {
+		byPath[item.Path] = item
+	}
+
+	bun := byPath["tools/bun/index.md"]
+	assert.Equal(t, "Bun", bun.Title)
+	assert.True(t, bun.Draft)
+	assert.Equal(t, time.Date(2025, 12, 27, 0, 0, 0, 0, time.UTC), bun.Date)
+
+	post := byPath["blog/post.md"]
+	assert.False(t, post.Draft)
+	assert.Equal(t, "My Post", post.Slug)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), post.ExpiryDate)
+
+	assert.Equal(t, "", byPath["about.md"].Title)
+}
+
+// TestContentItem_UnpublishedReason tests draft, future and expiry rules
+func TestContentItem_UnpublishedReason(t *testing.T) {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	past := now.AddDate(0, -1, 0)
+	future := now.AddDate(0, 1, 0)
+
+	assert.Equal(t, "", ContentItem{Date: past}.UnpublishedReason(now))
+	assert.Equal(t, "", ContentItem{}.UnpublishedReason(now))
+	assert.Equal(t, "draft", ContentItem{Draft: true, Date: past}.UnpublishedReason(now))
+	assert.Equal(t, "future-dated 2026-11-18", ContentItem{Date: future}.UnpublishedReason(now))
+	assert.Equal(t, "", ContentItem{Date: future, PublishDate: past}.UnpublishedReason(now))
+	assert.Equal(t, "expired 2026-09-18", ContentItem{Date: past, ExpiryDate: past}.UnpublishedReason(now))
+}
+
+// TestContentItem_URLPath tests file to URL mapping
+func TestContentItem_URLPath(t *testing.T) {
+	tests := []struct {
+		item ContentItem
+		want string
+	}{
+		{ContentItem{Path: "_index.md"}, "/"},
+		{ContentItem{Path: "about.md"}, "/about/"},
+		{ContentItem{Path: "blog/_index.md"}, "/blog/"},
+		{ContentItem{Path: "tools/bun/index.md"}, "/tools/bun/"},
+		{ContentItem{Path: "blog/posts/My Post.md"}, "/blog/posts/my-post/"},
+		{ContentItem{Path: "blog/posts/2025/x/index.md", Slug: "grokipedia"}, "/blog/posts/2025/grokipedia/"},
+		{ContentItem{Path: "privacy.md", URL: "/legal/privacy"}, "/legal/privacy/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.item.Path, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.item.URLPath())
+		})
+	}
+}
+
+// TestLoadContent_Site checks every site content file has parseable frontmatter
+func TestLoadContent_Site(t *testing.T) {
+	items, err := LoadContent(filepath.Join("..", ContentDir))
+	require.NoError(t, err)
+	assert.NotEmpty(t, items)
+}
@@ -0,0 +1,185 @@
+package support
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// FeatureFlagsDataPath is the site's feature flag declarations
+const FeatureFlagsDataPath = SiteDir + "/data/feature-flags.toml"
+
+// Scenario tags gated on feature flags
+const (
+	// FlagOnTagPrefix runs a scenario only when the flag is enabled (@flag:<name>)
+	FlagOnTagPrefix = "@flag:"
+	// FlagOffTagPrefix runs a scenario only when the flag is disabled (@flag-off:<name>)
+	FlagOffTagPrefix = "@flag-off:"
+)
+
+// FeatureFlag is a single entry under [flags] in data/feature-flags.toml
+type FeatureFlag struct {
+	Enabled           bool   `toml:"enabled"`
+	Description       string `toml:"description"`
+	RolloutPercentage int    `toml:"rollout_percentage"`
+}
+
+// FeatureFlags maps flag names to their declarations
+type FeatureFlags map[string]FeatureFlag
+
+// featureFlagsFile mirrors the top level of data/feature-flags.toml
+type featureFlagsFile struct {
+	Flags FeatureFlags `toml:"flags"`
+}
+
+// LoadFeatureFlags reads the feature flag declarations
+func LoadFeatureFlags(path string) (FeatureFlags, error) {
+	var file featureFlagsFile
+	if _, err := toml.DecodeFile(path, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse feature flags %s: %w", path, err)
+	}
+	if file.Flags == nil {
+		file.Flags = FeatureFlags{}
+	}
+	return file.Flags, nil
+}
+
+// FeatureFlagsFromEnv loads the flags file and applies FEATURE_FLAGS overrides,
+// e.g. FEATURE_FLAGS="books_section=on,cookie_consent_banner=off"
+func FeatureFlagsFromEnv() (FeatureFlags, error) {
+	flags, err := LoadFeatureFlags(FeatureFlagsDataPath)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := ParseFlagOverrides(os.Getenv("FEATURE_FLAGS"))
+	if err != nil {
+		return nil, err
+	}
+
+	return flags.WithOverrides(overrides)
+}
+
+// ParseFlagOverrides parses a comma-separated list of name=bool pairs
+func ParseFlagOverrides(spec string) (map[string]bool, error) {
+	overrides := make(map[string]bool)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid flag override %q, expected name=on|off", pair)
+		}
+
+		enabled, err := parseFlagValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for flag %s: %w", name, err)
+		}
+		overrides[strings.TrimSpace(name)] = enabled
+	}
+	return overrides, nil
+}
+
+// parseFlagValue accepts on/off in addition to strconv booleans
+func parseFlagValue(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on", "yes":
+		return true, nil
+	case "off", "no":
+		return false, nil
+	default:
+		return strconv.ParseBool(strings.TrimSpace(value))
+	}
+}
+
+// WithOverrides returns a copy with the given flags forced on or off.
+// Overriding an undeclared flag is an error so typos don't silently pass.
+func (f FeatureFlags) WithOverrides(overrides map[string]bool) (FeatureFlags, error) {
+	result := make(FeatureFlags, len(f))
+	for name, flag := range f {
+		result[name] = flag
+	}
+
+	for name, enabled := range overrides {
+		flag, ok := result[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown feature flag %q (known: %s)", name, strings.Join(f.Names(), ", "))
+		}
+		flag.Enabled = enabled
+		if enabled && flag.RolloutPercentage == 0 {
+			flag.RolloutPercentage = 100
+		}
+		result[name] = flag
+	}
+
+	return result, nil
+}
+
+// IsEnabled reports whether a flag is on. Rollout percentages are ignored so
+// scenarios are deterministic; a partially rolled out flag counts as enabled.
+func (f FeatureFlags) IsEnabled(name string) bool {
+	return f[name].Enabled
+}
+
+// Names returns the flag names in sorted order
+func (f FeatureFlags) Names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TagFilter returns a godog tag expression excluding scenarios whose flag
+// precondition does not hold
+func (f FeatureFlags) TagFilter() string {
+	var excluded []string
+	for _, name := range f.Names() {
+		if f.IsEnabled(name) {
+			excluded = append(excluded, "~"+FlagOffTagPrefix+name)
+		} else {
+			excluded = append(excluded, "~"+FlagOnTagPrefix+name)
+		}
+	}
+	return strings.Join(excluded, " && ")
+}
+
+// CombineTagFilters joins godog tag expressions with AND, skipping empty ones
+func CombineTagFilters(filters ...string) string {
+	var parts []string
+	for _, filter := range filters {
+		if strings.TrimSpace(filter) != "" {
+			parts = append(parts, filter)
+		}
+	}
+	return strings.Join(parts, " && ")
+}
+
+// WriteHugoConfig writes a config layer exposing the flags as site.Params.Flags,
+// which the components/feature-flag.html partial reads ahead of the data file
+func (f FeatureFlags) WriteHugoConfig(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create flags config %s: %w", path, err)
+	}
+	defer file.Close()
+
+	config := map[string]interface{}{
+		"params": map[string]interface{}{
+			"flags": f,
+		},
+	}
+	if err := toml.NewEncoder(file).Encode(config); err != nil {
+		return fmt.Errorf("failed to write flags config %s: %w", path, err)
+	}
+
+	return nil
+}
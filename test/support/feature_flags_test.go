@@ -0,0 +1,94 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadFeatureFlags_SiteData tests parsing the site's flag declarations
+func TestLoadFeatureFlags_SiteData(t *testing.T) {
+	flags, err := LoadFeatureFlags(filepath.Join("..", FeatureFlagsDataPath))
+	require.NoError(t, err)
+
+	require.Contains(t, flags, "cookie_consent_banner")
+	assert.True(t, flags.IsEnabled("cookie_consent_banner"))
+	assert.Equal(t, 100, flags["cookie_consent_banner"].RolloutPercentage)
+	assert.False(t, flags.IsEnabled("discord_social_link"))
+}
+
+// TestParseFlagOverrides tests the FEATURE_FLAGS syntax
+func TestParseFlagOverrides(t *testing.T) {
+	overrides, err := ParseFlagOverrides("books_section=on, cookie_consent_banner=false,,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"books_section": true, "cookie_consent_banner": false}, overrides)
+
+	_, err = ParseFlagOverrides("books_section")
+	assert.ErrorContains(t, err, "expected name=on|off")
+
+	_, err = ParseFlagOverrides("books_section=maybe")
+	assert.ErrorContains(t, err, "books_section")
+}
+
+// TestFeatureFlags_WithOverrides tests overriding without mutating the original
+func TestFeatureFlags_WithOverrides(t *testing.T) {
+	flags := FeatureFlags{
+		"books_section": {Enabled: false, RolloutPercentage: 0},
+		"recruiter_cta": {Enabled: true, RolloutPercentage: 100},
+	}
+
+	overridden, err := flags.WithOverrides(map[string]bool{"books_section": true, "recruiter_cta": false})
+	require.NoError(t, err)
+	assert.True(t, overridden.IsEnabled("books_section"))
+	assert.Equal(t, 100, overridden["books_section"].RolloutPercentage)
+	assert.False(t, overridden.IsEnabled("recruiter_cta"))
+	assert.False(t, flags.IsEnabled("books_section"))
+
+	_, err = flags.WithOverrides(map[string]bool{"book_section": true})
+	assert.ErrorContains(t, err, `unknown feature flag "book_section"`)
+}
+
+// TestFeatureFlags_TagFilter tests the generated godog tag expression
+func TestFeatureFlags_TagFilter(t *testing.T) {
+	flags := FeatureFlags{
+		"books_section": {Enabled: false},
+		"recruiter_cta": {Enabled: true},
+	}
+
+	assert.Equal(t, "~@flag:books_section && ~@flag-off:recruiter_cta", flags.TagFilter())
+	assert.Equal(t, "", FeatureFlags{}.TagFilter())
+}
+
+// TestCombineTagFilters tests joining tag expressions
+func TestCombineTagFilters(t *testing.T) {
+	assert.Equal(t, "@smoke && ~@flag:x", CombineTagFilters("@smoke", "", "~@flag:x"))
+	assert.Equal(t, "", CombineTagFilters("", " "))
+}
+
+// TestFeatureFlags_WriteHugoConfig tests the params.flags config layer
+func TestFeatureFlags_WriteHugoConfig(t *testing.T) {
+	flags := FeatureFlags{
+		"cookie_consent_banner": {Enabled: false, Description: "GDPR cookie consent banner", RolloutPercentage: 100},
+	}
+
+	path := filepath.Join(t.TempDir(), "flags.toml")
+	require.NoError(t, flags.WriteHugoConfig(path))
+
+	var config struct {
+		Params struct {
+			Flags FeatureFlags `toml:"flags"`
+		} `toml:"params"`
+	}
+	_, err := toml.DecodeFile(path, &config)
+	require.NoError(t, err)
+	assert.Equal(t, flags, config.Params.Flags)
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(raw), "[params.flags.cookie_consent_banner]"))
+}
@@ -0,0 +1,234 @@
+package support
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Feed is an RSS 2.0 channel or Atom feed reduced to what the checks need
+type Feed struct {
+	Format string // "rss" or "atom"
+	Title  string
+	Link   string
+	Items  []FeedItem
+}
+
+// FeedItem is an RSS <item> or Atom <entry>
+type FeedItem struct {
+	Title   string
+	Link    string
+	RawDate string // pubDate, or published/updated for Atom
+}
+
+// feedLink covers RSS <link>URL</link>, Atom <link href=""/> and the
+// atom:link self reference Hugo adds to RSS
+type feedLink struct {
+	Href  string `xml:"href,attr"`
+	Rel   string `xml:"rel,attr"`
+	Value string `xml:",chardata"`
+}
+
+// url returns the link's target if it points at the page, not the feed itself
+func (l feedLink) url() string {
+	if l.Rel != "" && l.Rel != "alternate" {
+		return ""
+	}
+	if l.Href != "" {
+		return strings.TrimSpace(l.Href)
+	}
+	return strings.TrimSpace(l.Value)
+}
+
+// firstLink returns the first page link in a list
+func firstLink(links []feedLink) string {
+	for _, l := range links {
+		if u := l.url(); u != "" {
+			return u
+		}
+	}
+	return ""
+}
+
+type rssDocument struct {
+	Channel struct {
+		Title string     `xml:"title"`
+		Links []feedLink `xml:"link"`
+		Items []struct {
+			Title   string     `xml:"title"`
+			Links   []feedLink `xml:"link"`
+			PubDate string     `xml:"pubDate"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+type atomDocument struct {
+	Title   string     `xml:"title"`
+	Links   []feedLink `xml:"link"`
+	Entries []struct {
+		Title     string     `xml:"title"`
+		Links     []feedLink `xml:"link"`
+		Published string     `xml:"published"`
+		Updated   string     `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// LoadFeed parses a feed from a build, e.g. blog/index.xml
+func LoadFeed(site *BuiltSite, rel string) (*Feed, error) {
+	data, err := os.ReadFile(filepath.Join(site.Dir, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed %s: %w", rel, err)
+	}
+	feed, err := ParseFeed(data)
+	if err != nil {
+		return nil, fmt.Errorf("feed %s: %w", rel, err)
+	}
+	return feed, nil
+}
+
+// ParseFeed parses an RSS 2.0 or Atom document
+func ParseFeed(data []byte) (*Feed, error) {
+	var root struct{ XMLName xml.Name }
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("not valid XML: %w", err)
+	}
+
+	switch root.XMLName.Local {
+	case "rss":
+		var doc rssDocument
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid RSS: %w", err)
+		}
+		feed := &Feed{Format: "rss", Title: doc.Channel.Title, Link: firstLink(doc.Channel.Links)}
+		for _, item := range doc.Channel.Items {
+			feed.Items = append(feed.Items, FeedItem{
+				Title:   strings.TrimSpace(item.Title),
+				Link:    firstLink(item.Links),
+				RawDate: strings.TrimSpace(item.PubDate),
+			})
+		}
+		return feed, nil
+	case "feed":
+		var doc atomDocument
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid Atom: %w", err)
+		}
+		feed := &Feed{Format: "atom", Title: doc.Title, Link: firstLink(doc.Links)}
+		for _, entry := range doc.Entries {
+			date := entry.Published
+			if date == "" {
+				date = entry.Updated
+			}
+			feed.Items = append(feed.Items, FeedItem{
+				Title:   strings.TrimSpace(entry.Title),
+				Link:    firstLink(entry.Links),
+				RawDate: strings.TrimSpace(date),
+			})
+		}
+		return feed, nil
+	default:
+		return nil, fmt.Errorf("root <%s> is neither <rss> nor <feed>", root.XMLName.Local)
+	}
+}
+
+// feedDateLayouts are the date formats valid in each feed format
+var feedDateLayouts = map[string][]string{
+	"rss":  {time.RFC1123Z, time.RFC1123, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST"},
+	"atom": {time.RFC3339},
+}
+
+// Date parses the item's date in the feed's format
+func (i FeedItem) Date(format string) (time.Time, error) {
+	for _, layout := range feedDateLayouts[format] {
+		if t, err := time.Parse(layout, i.RawDate); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid %s date %q", format, i.RawDate)
+}
+
+// Check returns items without a title or a valid date, and links that are
+// not absolute URLs on baseURL's host
+func (f *Feed) Check(baseURL string) []string {
+	siteHost := hostOf(baseURL)
+	var problems []string
+	checkLink := func(what, link string) {
+		u, err := url.Parse(link)
+		switch {
+		case link == "":
+			problems = append(problems, what+": missing link")
+		case err != nil || !u.IsAbs() || u.Host == "":
+			problems = append(problems, fmt.Sprintf("%s: link %q is not an absolute URL", what, link))
+		case !strings.EqualFold(u.Hostname(), siteHost):
+			problems = append(problems, fmt.Sprintf("%s: link %s is not on %s", what, link, siteHost))
+		}
+	}
+
+	checkLink("feed", f.Link)
+	for n, item := range f.Items {
+		what := fmt.Sprintf("item %d %q", n+1, item.Title)
+		if item.Title == "" {
+			problems = append(problems, what+": missing title")
+		}
+		checkLink(what, item.Link)
+		if _, err := item.Date(f.Format); err != nil {
+			problems = append(problems, what+": "+err.Error())
+		}
+	}
+	return problems
+}
+
+// FeedPosts returns the regular pages under a content section that a build
+// with opts publishes, newest first
+func FeedPosts(items []ContentItem, section string, now time.Time, opts PublishOptions) []ContentItem {
+	var posts []ContentItem
+	for _, item := range items {
+		if !strings.HasPrefix(item.Path, section+"/") || item.IsSection() || !item.PublishedWith(now, opts) {
+			continue
+		}
+		posts = append(posts, item)
+	}
+
+	sort.SliceStable(posts, func(i, j int) bool {
+		return posts[i].sortDate().After(posts[j].sortDate())
+	})
+	return posts
+}
+
+// sortDate is the date Hugo orders pages by
+func (c ContentItem) sortDate() time.Time {
+	if c.Date.IsZero() {
+		return c.PublishDate
+	}
+	return c.Date
+}
+
+// MissingFromFeed returns the newest n posts the feed does not link to.
+// Posts tied on date with the first post past n are not required.
+func MissingFromFeed(feed *Feed, posts []ContentItem, n int) []ContentItem {
+	linked := make(map[string]bool, len(feed.Items))
+	for _, item := range feed.Items {
+		if u, err := url.Parse(item.Link); err == nil {
+			linked[ensureTrailingSlash(u.Path)] = true
+		}
+	}
+
+	if n > len(posts) {
+		n = len(posts)
+	}
+	var missing []ContentItem
+	for i, post := range posts[:n] {
+		if n < len(posts) && post.sortDate().Equal(posts[n].sortDate()) {
+			continue
+		}
+		if !linked[post.URLPath()] {
+			missing = append(missing, posts[i])
+		}
+	}
+	return missing
+}
@@ -0,0 +1,113 @@
+package support
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRSS = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>Blog on Example</title>
+    <link>https://example.com/blog/</link>
+    <atom:link href="https://example.com/blog/index.xml" rel="self" type="application/rss+xml" />
+    <item>
+      <title>Newest</title>
+      <link>https://example.com/blog/posts/newest/</link>
+      <pubDate>Sat, 10 Oct 2026 00:00:00 +0000</pubDate>
+    </item>
+    <item>
+      <title>Broken</title>
+      <link>/blog/posts/broken/</link>
+      <pubDate>2026-10-01</pubDate>
+    </item>
+  </channel>
+</rss>`
+
+// TestParseFeed_RSS tests the channel link is not confused with atom:link
+func TestParseFeed_RSS(t *testing.T) {
+	feed, err := ParseFeed([]byte(testRSS))
+	require.NoError(t, err)
+
+	assert.Equal(t, "rss", feed.Format)
+	assert.Equal(t, "https://example.com/blog/", feed.Link)
+	require.Len(t, feed.Items, 2)
+	date, err := feed.Items[0].Date(feed.Format)
+	require.NoError(t, err)
+	assert.Equal(t, 2026, date.Year())
+
+	assert.Equal(t, []string{
+		`item 2 "Broken": link "/blog/posts/broken/" is not an absolute URL`,
+		`item 2 "Broken": invalid rss date "2026-10-01"`,
+	}, feed.Check("https://example.com/"))
+}
+
+// TestParseFeed_Atom tests Atom entries use href links and RFC 3339 dates
+func TestParseFeed_Atom(t *testing.T) {
+	feed, err := ParseFeed([]byte(`<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example</title>
+  <link href="https://example.com/feed.xml" rel="self"/>
+  <link href="https://example.com/"/>
+  <entry>
+    <title>Post</title>
+    <link href="https://example.com/blog/post/"/>
+    <updated>2026-10-10T00:00:00Z</updated>
+  </entry>
+</feed>`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://example.com/", feed.Link)
+	assert.Empty(t, feed.Check("https://example.com"))
+}
+
+// TestParseFeed_NotAFeed tests other XML documents are rejected
+func TestParseFeed_NotAFeed(t *testing.T) {
+	_, err := ParseFeed([]byte(`<urlset></urlset>`))
+	assert.ErrorContains(t, err, "neither <rss> nor <feed>")
+}
+
+// TestMissingFromFeed tests the newest published posts must be linked
+func TestMissingFromFeed(t *testing.T) {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	items := []ContentItem{
+		{Path: "blog/_index.md"},
+		{Path: "blog/posts/newest/index.md", Date: day(10)},
+		{Path: "blog/posts/draft.md", Date: day(12), Draft: true},
+		{Path: "blog/posts/scheduled.md", Date: day(30)},
+		{Path: "blog/posts/older.md", Date: day(5)},
+		{Path: "blog/posts/oldest.md", Date: day(1)},
+		{Path: "tools/bun/index.md", Date: day(11)},
+	}
+
+	posts := FeedPosts(items, "blog", now, PublishOptions{})
+	require.Len(t, posts, 3)
+	assert.Equal(t, "blog/posts/newest/index.md", posts[0].Path)
+
+	withFuture := FeedPosts(items, "blog", now, PublishOptions{Future: true})
+	assert.Equal(t, "blog/posts/scheduled.md", withFuture[0].Path)
+
+	feed, err := ParseFeed([]byte(testRSS))
+	require.NoError(t, err)
+	missing := MissingFromFeed(feed, posts, 2)
+	require.Len(t, missing, 1)
+	assert.Equal(t, "blog/posts/older.md", missing[0].Path)
+}
+
+// TestEnvironmentPublishOptions tests publish settings come from the merged config
+func TestEnvironmentPublishOptions(t *testing.T) {
+	t.Chdir("..")
+
+	development, err := EnvironmentPublishOptions("development")
+	require.NoError(t, err)
+	// buildDrafts sits under [params] in config/development/hugo.toml, so only
+	// the site config's buildFuture applies (see config-drift-policy.toml)
+	assert.Equal(t, PublishOptions{Future: true}, development)
+
+	production, err := EnvironmentPublishOptions("production")
+	require.NoError(t, err)
+	assert.False(t, production.Drafts)
+}
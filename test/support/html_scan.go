@@ -0,0 +1,231 @@
+package support
+
+import (
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// HTMLTag is a start tag found in generated HTML. Scanning is regex based,
+// which is reliable for Hugo output (including minified, unquoted attributes)
+// but not a general HTML parser.
+type HTMLTag struct {
+	Name  string
+	Attrs map[string]string
+	Raw   string
+	Text  string // body of <script> and <style> elements
+	Line  int
+}
+
+// Attr returns an attribute value, unescaped
+func (t HTMLTag) Attr(name string) string {
+	return t.Attrs[strings.ToLower(name)]
+}
+
+// HasAttr reports whether the attribute is present, even without a value
+func (t HTMLTag) HasAttr(name string) bool {
+	_, ok := t.Attrs[strings.ToLower(name)]
+	return ok
+}
+
+var (
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlTagPattern     = regexp.MustCompile(`(?is)<([a-z][a-z0-9-]*)((?:\s+[^\s"'>/=]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*)\s*/?>`)
+	htmlAttrPattern    = regexp.MustCompile(`(?s)([^\s"'>/=]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'=<>` + "`" + `]+)))?`)
+)
+
+// rawTextElements hold text that must not be scanned for tags
+var rawTextElements = map[string]bool{"script": true, "style": true}
+
+// ParseTags returns the start tags in document order, skipping comments.
+// Tags inside <script> and <style> are not reported; their body is in Text.
+func ParseTags(document string) []HTMLTag {
+	// Blank out comments but keep offsets so line numbers stay right
+	document = htmlCommentPattern.ReplaceAllStringFunc(document, func(c string) string {
+		return strings.Map(func(r rune) rune {
+			if r == '\n' {
+				return r
+			}
+			return ' '
+		}, c)
+	})
+
+	var tags []HTMLTag
+	lower := strings.ToLower(document)
+	pos := 0
+	for pos < len(document) {
+		loc := htmlTagPattern.FindStringSubmatchIndex(document[pos:])
+		if loc == nil {
+			break
+		}
+
+		start, end := pos+loc[0], pos+loc[1]
+		tag := HTMLTag{
+			Name:  strings.ToLower(document[pos+loc[2] : pos+loc[3]]),
+			Attrs: parseAttrs(document[pos+loc[4] : pos+loc[5]]),
+			Raw:   document[start:end],
+			Line:  strings.Count(document[:start], "\n") + 1,
+		}
+		pos = end
+
+		if rawTextElements[tag.Name] {
+			closing := strings.Index(lower[pos:], "</"+tag.Name)
+			if closing < 0 {
+				closing = len(document) - pos
+			}
+			tag.Text = document[pos : pos+closing]
+			pos += closing
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// parseAttrs parses an attribute list into lower-cased names and unescaped values
+func parseAttrs(raw string) map[string]string {
+	attrs := make(map[string]string)
+	for _, m := range htmlAttrPattern.FindAllStringSubmatch(raw, -1) {
+		name := strings.ToLower(m[1])
+		if _, seen := attrs[name]; seen {
+			// The first occurrence wins, as in browsers
+			continue
+		}
+		attrs[name] = html.UnescapeString(m[2] + m[3] + m[4])
+	}
+	return attrs
+}
+
+// FindTags returns the start tags with the given element name
+func FindTags(document, name string) []HTMLTag {
+	var result []HTMLTag
+	for _, tag := range ParseTags(document) {
+		if tag.Name == name {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// SitePage is a generated HTML page in a build directory
+type SitePage struct {
+	Path string // file path on disk
+	URL  string // site-relative URL path, e.g. /blog/
+}
+
+// SitePages lists the HTML pages in a build directory, sorted by URL
+func SitePages(dir string) ([]SitePage, error) {
+	var pages []SitePage
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".html" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		pages = append(pages, SitePage{Path: path, URL: pageURLPath(rel)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pages, func(i, j int) bool { return pages[i].URL < pages[j].URL })
+	return pages, nil
+}
+
+// pageURLPath maps a build-relative file to the URL it is served at
+func pageURLPath(rel string) string {
+	urlPath := "/" + filepath.ToSlash(rel)
+	if strings.HasSuffix(urlPath, "/index.html") {
+		return strings.TrimSuffix(urlPath, "index.html")
+	}
+	return urlPath
+}
+
+// Read returns the page's HTML
+func (p SitePage) Read() (string, error) {
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// IsRedirectStub reports whether a page is one of Hugo's alias redirect pages,
+// which have no head partials and are skipped by per-page checks
+func IsRedirectStub(document string) bool {
+	for _, meta := range FindTags(document, "meta") {
+		if strings.EqualFold(meta.Attr("http-equiv"), "refresh") {
+			return true
+		}
+	}
+	return false
+}
+
+// PageFindings maps page URLs to the problems found on them
+type PageFindings map[string][]string
+
+// ScanSitePages runs check on every page of a build, skipping alias redirects
+func ScanSitePages(dir string, check func(document string) []string) (PageFindings, error) {
+	pages, err := SitePages(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pages in %s: %w", dir, err)
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no HTML pages found in %s", dir)
+	}
+
+	findings := make(PageFindings)
+	for _, page := range pages {
+		document, err := page.Read()
+		if err != nil {
+			return nil, err
+		}
+		if IsRedirectStub(document) {
+			continue
+		}
+		if problems := check(document); len(problems) > 0 {
+			findings[page.URL] = problems
+		}
+	}
+	return findings, nil
+}
+
+// Count returns the total number of problems across pages
+func (f PageFindings) Count() int {
+	total := 0
+	for _, problems := range f {
+		total += len(problems)
+	}
+	return total
+}
+
+// String renders findings grouped by page, in URL order
+func (f PageFindings) String() string {
+	urls := make([]string, 0, len(f))
+	for u := range f {
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+
+	var b strings.Builder
+	for _, u := range urls {
+		fmt.Fprintf(&b, "  %s\n", u)
+		for _, problem := range f[u] {
+			fmt.Fprintf(&b, "    - %s\n", problem)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
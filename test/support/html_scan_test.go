@@ -0,0 +1,83 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseTags tests tag and attribute extraction from generated HTML
+func TestParseTags(t *testing.T) {
+	t.Run("quoted, unquoted and boolean attributes", func(t *testing.T) {
+		tags := ParseTags(`<a href=/blog/ class="btn primary" data-x='1 &amp; 2' hidden>Blog</a>`)
+		require.Len(t, tags, 1)
+
+		tag := tags[0]
+		assert.Equal(t, "a", tag.Name)
+		assert.Equal(t, "/blog/", tag.Attr("href"))
+		assert.Equal(t, "btn primary", tag.Attr("class"))
+		assert.Equal(t, "1 & 2", tag.Attr("data-x"))
+		assert.True(t, tag.HasAttr("hidden"))
+		assert.False(t, tag.HasAttr("target"))
+	})
+
+	t.Run("script bodies are not scanned for tags", func(t *testing.T) {
+		doc := "<head>\n<script>document.write('<img src=x>')</script>\n<img src=\"/a.png\"></head>"
+		tags := ParseTags(doc)
+
+		var names []string
+		for _, tag := range tags {
+			names = append(names, tag.Name)
+		}
+		assert.Equal(t, []string{"head", "script", "img"}, names)
+		assert.Contains(t, tags[1].Text, "document.write")
+		assert.Equal(t, 3, tags[2].Line)
+	})
+
+	t.Run("comments are skipped", func(t *testing.T) {
+		tags := FindTags("<!-- <script src=http://old></script> -->\n<script src=/new.js></script>", "script")
+		require.Len(t, tags, 1)
+		assert.Equal(t, "/new.js", tags[0].Attr("src"))
+		assert.Equal(t, 2, tags[0].Line)
+	})
+}
+
+// TestSitePages tests build directory listing and URL mapping
+func TestSitePages(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"index.html", "blog/index.html", "404.html", "index.xml"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte("<html></html>"), 0o644))
+	}
+
+	pages, err := SitePages(dir)
+	require.NoError(t, err)
+
+	var urls []string
+	for _, page := range pages {
+		urls = append(urls, page.URL)
+	}
+	assert.Equal(t, []string{"/", "/404.html", "/blog/"}, urls)
+}
+
+// TestScanSitePages tests findings are collected per page and redirects skipped
+func TestScanSitePages(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte(`<a target=_blank href=https://x.com>X</a>`), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "old"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "old", "index.html"),
+		[]byte(`<meta http-equiv="refresh" content="0; url=/new/"><a target=_blank href=/new/>`), 0o644))
+
+	findings, err := ScanSitePages(dir, BlankTargetsWithoutNoopener)
+	require.NoError(t, err)
+	assert.Equal(t, 1, findings.Count())
+	assert.Contains(t, findings, "/")
+	assert.Contains(t, findings.String(), "https://x.com")
+
+	_, err = ScanSitePages(t.TempDir(), BlankTargetsWithoutNoopener)
+	assert.ErrorContains(t, err, "no HTML pages")
+}
@@ -2,6 +2,7 @@ package support
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"time"
 )
@@ -11,6 +12,10 @@ type HugoServer struct {
 	cmd       *exec.Cmd
 	baseURL   string
 	isRunning bool
+
+	// Flags, when set, are mirrored into site.Params.Flags for the server
+	Flags       FeatureFlags
+	flagsConfig string
 }
 
 // NewHugoServer creates a new Hugo server instance
@@ -32,6 +37,15 @@ func (h *HugoServer) Start() error {
 		return nil
 	}
 
+	config := "config/development/hugo.toml"
+	if h.Flags != nil {
+		flagsConfig, err := h.writeFlagsConfig()
+		if err != nil {
+			return err
+		}
+		config = flagsConfig + "," + config
+	}
+
 	// Start Hugo server with port 0 to let OS assign available port
 	h.cmd = exec.Command("hugo", "server",
 		"-D",
@@ -40,7 +54,7 @@ func (h *HugoServer) Start() error {
 		"--baseURL", h.baseURL,
 		"--navigateToChanged",
 		"--disableFastRender",
-		"--config", "config/development/hugo.toml",
+		"--config", config,
 	)
 
 	h.cmd.Dir = ".." // Run from project root
@@ -72,9 +86,35 @@ func (h *HugoServer) Stop() error {
 
 	h.isRunning = false
 	h.cmd = nil
+	h.removeFlagsConfig()
 	return nil
 }
 
+// writeFlagsConfig writes the feature flag config layer to a temp file
+func (h *HugoServer) writeFlagsConfig() (string, error) {
+	file, err := os.CreateTemp("", "hugo-flags-*.toml")
+	if err != nil {
+		return "", fmt.Errorf("failed to create flags config: %w", err)
+	}
+	file.Close()
+
+	if err := h.Flags.WriteHugoConfig(file.Name()); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	h.flagsConfig = file.Name()
+	return h.flagsConfig, nil
+}
+
+// removeFlagsConfig deletes the temp flags config, if any
+func (h *HugoServer) removeFlagsConfig() {
+	if h.flagsConfig != "" {
+		os.Remove(h.flagsConfig)
+		h.flagsConfig = ""
+	}
+}
+
 // IsServerRunning checks if Hugo server is responding
 func (h *HugoServer) IsServerRunning() bool {
 	cmd := exec.Command("curl", "-s", "-o", "/dev/null", "-w", "%{http_code}", h.baseURL+"/")
@@ -0,0 +1,178 @@
+package support
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Issue statuses used by bd
+const (
+	IssueOpen       = "open"
+	IssueInProgress = "in_progress"
+	IssueClosed     = "closed"
+)
+
+// Issue is a tracker issue in the .beads/issues.jsonl schema
+type Issue struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Status      string     `json:"status"`
+	Priority    int        `json:"priority"`
+	IssueType   string     `json:"issue_type"`
+	Labels      []string   `json:"labels,omitempty"`
+	CloseReason string     `json:"close_reason,omitempty"`
+	CreatedAt   time.Time  `json:"created_at,omitzero"`
+	UpdatedAt   time.Time  `json:"updated_at,omitzero"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+}
+
+// IsOpen reports whether the issue still needs work
+func (i Issue) IsOpen() bool {
+	return i.Status != IssueClosed
+}
+
+// IssueFilter narrows List; zero values match everything
+type IssueFilter struct {
+	Status string
+	Label  string
+}
+
+// Matches reports whether an issue passes the filter
+func (f IssueFilter) Matches(issue Issue) bool {
+	if f.Status != "" && issue.Status != f.Status {
+		return false
+	}
+	if f.Label != "" && !containsString(issue.Labels, f.Label) {
+		return false
+	}
+	return true
+}
+
+// IssueTracker is where accessibility findings are filed
+type IssueTracker interface {
+	// List returns the issues matching filter, open and closed
+	List(filter IssueFilter) ([]Issue, error)
+	// Create files a new issue and returns it with its assigned ID
+	Create(issue Issue) (Issue, error)
+	// Update replaces the title, description, status, priority and labels of issue.ID
+	Update(issue Issue) error
+	// Close closes an issue, recording why
+	Close(id, reason string) error
+}
+
+// MemoryTracker is an in-memory IssueTracker for tests and dry runs
+type MemoryTracker struct {
+	mu     sync.Mutex
+	issues map[string]Issue
+	nextID int
+	now    func() time.Time
+}
+
+// NewMemoryTracker creates a tracker seeded with issues
+func NewMemoryTracker(issues ...Issue) *MemoryTracker {
+	t := &MemoryTracker{issues: make(map[string]Issue), now: time.Now}
+	for _, issue := range issues {
+		t.issues[issue.ID] = issue
+	}
+	return t
+}
+
+// List returns matching issues ordered by ID
+func (t *MemoryTracker) List(filter IssueFilter) ([]Issue, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var issues []Issue
+	for _, issue := range t.issues {
+		if filter.Matches(issue) {
+			issues = append(issues, issue)
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].ID < issues[j].ID })
+	return issues, nil
+}
+
+// Create assigns the next mem-N ID and stores the issue as open
+func (t *MemoryTracker) Create(issue Issue) (Issue, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for {
+		t.nextID++
+		issue.ID = fmt.Sprintf("mem-%d", t.nextID)
+		if _, taken := t.issues[issue.ID]; !taken {
+			break
+		}
+	}
+	if issue.Status == "" {
+		issue.Status = IssueOpen
+	}
+	issue.CreatedAt = t.now().UTC()
+	issue.UpdatedAt = issue.CreatedAt
+	t.issues[issue.ID] = issue
+	return issue, nil
+}
+
+// Update replaces the editable fields of an existing issue
+func (t *MemoryTracker) Update(issue Issue) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	existing, ok := t.issues[issue.ID]
+	if !ok {
+		return fmt.Errorf("issue %s not found", issue.ID)
+	}
+	t.issues[issue.ID] = applyIssueUpdate(existing, issue, t.now().UTC())
+	return nil
+}
+
+// Close marks an issue closed
+func (t *MemoryTracker) Close(id, reason string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	existing, ok := t.issues[id]
+	if !ok {
+		return fmt.Errorf("issue %s not found", id)
+	}
+	t.issues[id] = closeIssue(existing, reason, t.now().UTC())
+	return nil
+}
+
+// Get returns one issue, for assertions
+func (t *MemoryTracker) Get(id string) (Issue, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	issue, ok := t.issues[id]
+	return issue, ok
+}
+
+// applyIssueUpdate copies the editable fields of update onto existing.
+// Reopening clears the close metadata.
+func applyIssueUpdate(existing, update Issue, now time.Time) Issue {
+	existing.Title = update.Title
+	existing.Description = update.Description
+	existing.Priority = update.Priority
+	existing.Labels = update.Labels
+	if update.Status != "" {
+		existing.Status = update.Status
+	}
+	if existing.IsOpen() {
+		existing.ClosedAt = nil
+		existing.CloseReason = ""
+	}
+	existing.UpdatedAt = now
+	return existing
+}
+
+// closeIssue marks an issue closed at now
+func closeIssue(issue Issue, reason string, now time.Time) Issue {
+	issue.Status = IssueClosed
+	issue.CloseReason = reason
+	issue.ClosedAt = &now
+	issue.UpdatedAt = now
+	return issue
+}
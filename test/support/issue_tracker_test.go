@@ -0,0 +1,135 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMemoryTracker tests create, filter, close and reopen
+func TestMemoryTracker(t *testing.T) {
+	tracker := NewMemoryTracker(Issue{ID: "pw-1", Title: "Old", Status: IssueClosed, Labels: []string{"a11y"}})
+
+	created, err := tracker.Create(Issue{Title: "New", IssueType: "bug", Labels: []string{"a11y"}})
+	require.NoError(t, err)
+	assert.Equal(t, "mem-1", created.ID)
+	assert.Equal(t, IssueOpen, created.Status)
+
+	open, err := tracker.List(IssueFilter{Status: IssueOpen, Label: "a11y"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mem-1"}, issueIDs(open))
+
+	require.NoError(t, tracker.Close("mem-1", "fixed"))
+	closed, _ := tracker.Get("mem-1")
+	assert.Equal(t, "fixed", closed.CloseReason)
+	require.NotNil(t, closed.ClosedAt)
+
+	closed.Status = IssueOpen
+	require.NoError(t, tracker.Update(closed))
+	reopened, _ := tracker.Get("mem-1")
+	assert.True(t, reopened.IsOpen())
+	assert.Nil(t, reopened.ClosedAt)
+	assert.Empty(t, reopened.CloseReason)
+
+	assert.Error(t, tracker.Close("missing", "fixed"))
+}
+
+// issueIDs returns the IDs of issues, for assertions
+func issueIDs(issues []Issue) []string {
+	ids := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	return ids
+}
+
+// TestJSONLTracker tests edits keep other lines and unknown keys intact
+func TestJSONLTracker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "issues.jsonl")
+	untouched := `{"id":"pw-83r","title":"Release & notes","status":"closed","priority":0,"issue_type":"task","created_at":"2025-11-27T01:35:17Z","updated_at":"2025-11-27T01:35:20Z","closed_at":"2025-11-27T01:35:20Z","dependency_count":0}`
+	edited := `{"id":"pw-hd1","title":"Contrast","status":"open","priority":2,"issue_type":"bug","created_at":"2025-11-26T20:19:41Z","updated_at":"2025-11-26T20:19:41Z","dependencies":[{"issue_id":"pw-hd1","depends_on_id":"pw-duh"}],"owner":"peter@peterwarnock.com"}`
+	require.NoError(t, os.WriteFile(path, []byte(untouched+"\n"+edited+"\n"), 0o644))
+
+	tracker := NewJSONLTracker(path)
+	issues, err := tracker.List(IssueFilter{Status: IssueOpen})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "Contrast", issues[0].Title)
+
+	require.NoError(t, tracker.Close("pw-hd1", "fixed"))
+	created, err := tracker.Create(Issue{Title: "Alt text", IssueType: "bug", Priority: 1})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.ID, "pw-"), created.ID)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, untouched, lines[0])
+	assert.Contains(t, lines[1], `"status":"closed"`)
+	assert.Contains(t, lines[1], `"close_reason":"fixed"`)
+	assert.Contains(t, lines[1], `"dependencies":[{"issue_id":"pw-hd1","depends_on_id":"pw-duh"}]`)
+	assert.Contains(t, lines[1], `"owner":"peter@peterwarnock.com"`)
+
+	all, err := NewJSONLTracker(path).List(IssueFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pw-83r", "pw-hd1", created.ID}, issueIDs(all))
+	assert.Equal(t, IssueOpen, all[2].Status)
+}
+
+// TestJSONLTracker_MissingFile tests a missing file is an empty tracker
+func TestJSONLTracker_MissingFile(t *testing.T) {
+	tracker := NewJSONLTracker(filepath.Join(t.TempDir(), ".beads", "issues.jsonl"))
+	issues, err := tracker.List(IssueFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	created, err := tracker.Create(Issue{Title: "First"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.ID, "bd-"), created.ID)
+}
+
+// TestBdTracker tests the bd arguments for each operation
+func TestBdTracker(t *testing.T) {
+	var calls [][]string
+	tracker := &BdTracker{Run: func(args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		switch args[0] {
+		case "list":
+			return []byte(`[{"id":"pw-1","title":"A","status":"open","priority":1,"issue_type":"bug","labels":["a11y"]},` +
+				`{"id":"pw-3","title":"C","status":"open","priority":1,"issue_type":"bug"}]`), nil
+		case "create":
+			return []byte(`{"id":"pw-2","title":"B","status":"open","priority":1,"issue_type":"bug"}`), nil
+		}
+		return []byte(`{}`), nil
+	}}
+
+	issues, err := tracker.List(IssueFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pw-1", "pw-3"}, issueIDs(issues))
+
+	issues, err = tracker.List(IssueFilter{Status: IssueOpen, Label: "a11y"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pw-1"}, issueIDs(issues), "labels are filtered after listing")
+
+	created, err := tracker.Create(Issue{Title: "B", IssueType: "bug", Priority: 1, Description: "d", Labels: []string{"a11y", "auto"}})
+	require.NoError(t, err)
+	assert.Equal(t, "pw-2", created.ID)
+
+	require.NoError(t, tracker.Update(Issue{ID: "pw-1", Title: "A", Description: "d", Priority: 2, Status: IssueOpen, Labels: []string{"a11y", "wcag-aa"}}))
+	require.NoError(t, tracker.Update(Issue{ID: "pw-1", Title: "A", Description: "d", Priority: 2}))
+	require.NoError(t, tracker.Close("pw-1", "fixed"))
+
+	assert.Equal(t, [][]string{
+		{"list", "--json"},
+		{"list", "--json", "--status", "open"},
+		{"create", "B", "-t", "bug", "-p", "1", "--description", "d", "--json", "--label", "a11y", "--label", "auto"},
+		{"update", "pw-1", "--title", "A", "--description", "d", "--priority", "2", "--label", "a11y", "--label", "wcag-aa", "--status", "open", "--json"},
+		{"update", "pw-1", "--title", "A", "--description", "d", "--priority", "2", "--json"},
+		{"close", "pw-1", "--reason", "fixed", "--json"},
+	}, calls)
+}
@@ -0,0 +1,252 @@
+package support
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BeadsIssuesPath is the bd issue export, relative to the test directory
+const BeadsIssuesPath = RootDir + "/.beads/issues.jsonl"
+
+// issueFields are the JSON keys Issue owns; other keys in a record are kept as-is
+var issueFields = []string{
+	"id", "title", "description", "status", "priority", "issue_type",
+	"labels", "close_reason", "created_at", "updated_at", "closed_at",
+}
+
+// jsonlRecord is one line of issues.jsonl. Unchanged lines are written back
+// byte for byte so edits keep the diff small.
+type jsonlRecord struct {
+	raw    []byte
+	fields map[string]json.RawMessage
+	issue  Issue
+	dirty  bool
+}
+
+// JSONLTracker reads and writes a .beads/issues.jsonl file directly, for
+// machines without bd
+type JSONLTracker struct {
+	Path string
+	// Prefix for new IDs; defaults to the prefix of existing issues
+	Prefix string
+
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewJSONLTracker creates a tracker backed by the JSONL file at path
+func NewJSONLTracker(path string) *JSONLTracker {
+	return &JSONLTracker{Path: path, now: time.Now}
+}
+
+// List returns matching issues in file order
+func (t *JSONLTracker) List(filter IssueFilter) ([]Issue, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	records, err := t.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	for _, record := range records {
+		if filter.Matches(record.issue) {
+			issues = append(issues, record.issue)
+		}
+	}
+	return issues, nil
+}
+
+// Create appends a new open issue with a fresh ID
+func (t *JSONLTracker) Create(issue Issue) (Issue, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	records, err := t.load()
+	if err != nil {
+		return Issue{}, err
+	}
+
+	issue.ID = t.newID(records)
+	if issue.Status == "" {
+		issue.Status = IssueOpen
+	}
+	issue.CreatedAt = t.now().UTC().Truncate(time.Second)
+	issue.UpdatedAt = issue.CreatedAt
+
+	records = append(records, &jsonlRecord{fields: map[string]json.RawMessage{}, issue: issue, dirty: true})
+	if err := t.save(records); err != nil {
+		return Issue{}, err
+	}
+	return issue, nil
+}
+
+// Update rewrites the editable fields of an existing issue
+func (t *JSONLTracker) Update(issue Issue) error {
+	return t.modify(issue.ID, func(existing Issue, now time.Time) Issue {
+		return applyIssueUpdate(existing, issue, now)
+	})
+}
+
+// Close marks an issue closed with a reason
+func (t *JSONLTracker) Close(id, reason string) error {
+	return t.modify(id, func(existing Issue, now time.Time) Issue {
+		return closeIssue(existing, reason, now)
+	})
+}
+
+// modify applies change to one issue and saves the file
+func (t *JSONLTracker) modify(id string, change func(Issue, time.Time) Issue) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	records, err := t.load()
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if record.issue.ID == id {
+			record.issue = change(record.issue, t.now().UTC().Truncate(time.Second))
+			record.dirty = true
+			return t.save(records)
+		}
+	}
+	return fmt.Errorf("issue %s not found in %s", id, t.Path)
+}
+
+// load parses the file; a missing file is an empty tracker
+func (t *JSONLTracker) load() ([]*jsonlRecord, error) {
+	data, err := os.ReadFile(t.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", t.Path, err)
+	}
+
+	var records []*jsonlRecord
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		record := &jsonlRecord{raw: append([]byte(nil), raw...)}
+		if err := json.Unmarshal(raw, &record.fields); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", t.Path, line, err)
+		}
+		if err := json.Unmarshal(raw, &record.issue); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", t.Path, line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", t.Path, err)
+	}
+	return records, nil
+}
+
+// save writes every record back through a temp file and rename
+func (t *JSONLTracker) save(records []*jsonlRecord) error {
+	var buf bytes.Buffer
+	for _, record := range records {
+		line := record.raw
+		if record.dirty {
+			encoded, err := record.encode()
+			if err != nil {
+				return err
+			}
+			line = encoded
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.Path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(t.Path), err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(t.Path), ".issues-*.jsonl")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", t.Path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", t.Path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", t.Path, err)
+	}
+	return os.Rename(tmp.Name(), t.Path)
+}
+
+// encode renders the issue in schema order followed by the record's other keys
+func (r *jsonlRecord) encode() ([]byte, error) {
+	encoded, err := json.Marshal(r.issue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode issue %s: %w", r.issue.ID, err)
+	}
+
+	var extra []string
+	for key := range r.fields {
+		if !containsString(issueFields, key) {
+			extra = append(extra, key)
+		}
+	}
+	if len(extra) == 0 {
+		return encoded, nil
+	}
+	sort.Strings(extra)
+
+	var buf bytes.Buffer
+	buf.Write(encoded[:len(encoded)-1])
+	for _, key := range extra {
+		name, _ := json.Marshal(key)
+		buf.WriteByte(',')
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(r.fields[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// newID returns an unused <prefix>-<3 base36 chars> ID like bd generates
+func (t *JSONLTracker) newID(records []*jsonlRecord) string {
+	prefix := t.Prefix
+	taken := make(map[string]bool, len(records))
+	for _, record := range records {
+		taken[record.issue.ID] = true
+		if i := strings.LastIndex(record.issue.ID, "-"); prefix == "" && i > 0 {
+			prefix = record.issue.ID[:i]
+		}
+	}
+	if prefix == "" {
+		prefix = "bd"
+	}
+
+	const alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+	for length := 3; ; length++ {
+		for attempt := 0; attempt < 100; attempt++ {
+			suffix := make([]byte, length)
+			for i := range suffix {
+				suffix[i] = alphabet[rand.Intn(len(alphabet))]
+			}
+			if id := prefix + "-" + string(suffix); !taken[id] {
+				return id
+			}
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package support
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// MaxFocusStops bounds how many times TabThrough presses Tab
+const MaxFocusStops = 200
+
+// FocusOrderAttribute is set on every element TabThrough focuses, so later
+// checks can tell which elements the keyboard reached
+const FocusOrderAttribute = "data-focus-order"
+
+// KeyboardRegions locate the parts of the page whose controls must be
+// reachable by keyboard
+var KeyboardRegions = map[string]string{
+	"navigation": `nav[aria-label="Main navigation"]`,
+	"cards":      ".card",
+	"carousel":   ".hero-carousel",
+}
+
+// FocusStop is one element focused while tabbing through a page
+type FocusStop struct {
+	Index     int    `json:"index"`
+	Tag       string `json:"tag"`
+	Role      string `json:"role"`
+	Name      string `json:"name"` // accessible name
+	Href      string `json:"href"`
+	Selector  string `json:"selector"`  // short CSS path for messages
+	Indicator string `json:"indicator"` // the focus style that appears on focus, "" when there is none
+}
+
+// String formats the stop as "3. link "Blog" (nav > ul > li > a)"
+func (s FocusStop) String() string {
+	return fmt.Sprintf("%d. %s %q (%s)", s.Index, s.Role, s.Name, s.Selector)
+}
+
+// IsFrame reports whether the stop is an iframe. Tab moves into the frame's
+// own document, so the frame is recorded as one stop and tabbing resumes
+// after it.
+func (s FocusStop) IsFrame() bool {
+	return s.Tag == "iframe"
+}
+
+// IsSkipLink reports whether the stop is an in-page "Skip to ..." link
+func (s FocusStop) IsSkipLink() bool {
+	return s.Role == "link" && strings.HasPrefix(s.Href, "#") && strings.HasPrefix(strings.ToLower(s.Name), "skip")
+}
+
+// focusInfoScript describes document.activeElement and marks it with its
+// position in the focus order. It returns null when focus is on the body or
+// has come back round to an element already visited. The indicator is the
+// outline or box-shadow that differs from the element's blurred style, with
+// transitions off so neither is read mid-animation; an element styled the
+// same either way has none.
+const focusInfoScript = `([attribute, index]) => {
+	const el = document.activeElement;
+	if (!el || el === document.body || el === document.documentElement) return null;
+	if (el.hasAttribute(attribute)) return null;
+	el.setAttribute(attribute, String(index));
+
+	const implicitRoles = { A: 'link', BUTTON: 'button', SELECT: 'combobox', TEXTAREA: 'textbox', SUMMARY: 'button', IFRAME: 'iframe' };
+	let role = el.getAttribute('role') || implicitRoles[el.tagName] || '';
+	if (el.tagName === 'A' && !el.hasAttribute('href') && !el.getAttribute('role')) role = 'generic';
+	if (el.tagName === 'INPUT') {
+		role = { checkbox: 'checkbox', radio: 'radio', button: 'button', submit: 'button', range: 'slider', search: 'searchbox' }[el.type] || 'textbox';
+	}
+
+	const text = (node) => (node ? (node.innerText || node.textContent || '') : '').replace(/\s+/g, ' ').trim();
+	let name = '';
+	const labelledBy = el.getAttribute('aria-labelledby');
+	if (labelledBy) name = labelledBy.split(/\s+/).map(id => text(document.getElementById(id))).join(' ').trim();
+	if (!name) name = (el.getAttribute('aria-label') || '').trim();
+	if (!name && el.labels && el.labels.length) name = text(el.labels[0]);
+	if (!name) name = text(el);
+	if (!name) {
+		const img = el.querySelector('img[alt], svg[aria-label]');
+		if (img) name = (img.getAttribute('alt') || img.getAttribute('aria-label') || '').trim();
+	}
+	if (!name) name = (el.getAttribute('title') || el.getAttribute('placeholder') || '').trim();
+
+	const selector = [];
+	for (let node = el; node && node !== document.body && selector.length < 4; node = node.parentElement) {
+		let part = node.tagName.toLowerCase();
+		if (node.id) { selector.unshift(part + '#' + node.id); break; }
+		const classes = Array.from(node.classList).filter(c => !c.includes(':')).slice(0, 2);
+		if (classes.length) part += '.' + classes.join('.');
+		selector.unshift(part);
+	}
+
+	const ring = () => {
+		const style = getComputedStyle(el);
+		const outline = style.outlineStyle !== 'none' && parseFloat(style.outlineWidth) > 0;
+		return {
+			outline: outline ? style.outlineStyle + ' ' + style.outlineWidth + ' ' + style.outlineColor : '',
+			outlineLabel: outline ? 'outline ' + style.outlineStyle + ' ' + style.outlineWidth : '',
+			boxShadow: style.boxShadow && style.boxShadow !== 'none' ? style.boxShadow : '',
+		};
+	};
+	const transition = el.style.getPropertyValue('transition');
+	const priority = el.style.getPropertyPriority('transition');
+	el.style.setProperty('transition', 'none', 'important');
+	const focused = ring();
+	el.blur();
+	const blurred = ring();
+	el.focus({ preventScroll: true, focusVisible: true });
+	el.style.setProperty('transition', transition, priority);
+	if (!el.getAttribute('style')) el.removeAttribute('style');
+
+	let indicator = '';
+	if (focused.outline && focused.outline !== blurred.outline) {
+		indicator = focused.outlineLabel;
+	} else if (focused.boxShadow && focused.boxShadow !== blurred.boxShadow) {
+		indicator = 'box-shadow';
+	}
+
+	return {
+		index: index,
+		tag: el.tagName.toLowerCase(),
+		role: role,
+		name: name,
+		href: el.getAttribute('href') || '',
+		selector: selector.join(' > '),
+		indicator: indicator,
+	};
+}`
+
+// resetFocusScript moves the sequential focus starting point to the top of
+// the document and clears marks from an earlier TabThrough
+const resetFocusScript = `(attribute) => {
+	document.querySelectorAll('[' + attribute + ']').forEach(el => el.removeAttribute(attribute));
+	const start = document.createElement('span');
+	start.tabIndex = -1;
+	document.body.prepend(start);
+	start.focus();
+	start.remove();
+	window.scrollTo(0, 0);
+}`
+
+// focusAfterScript moves the sequential focus starting point to just after
+// the focused element, so the next Tab skips the contents of a focused iframe
+const focusAfterScript = `() => {
+	const el = document.activeElement;
+	if (!el || el === document.body) return;
+	const start = document.createElement('span');
+	start.tabIndex = -1;
+	el.after(start);
+	start.focus({ preventScroll: true });
+	start.remove();
+}`
+
+// FocusAfter moves focus out of the focused element, e.g. an iframe, so the
+// next Tab lands on whatever follows it
+func FocusAfter(page playwright.Page) error {
+	if _, err := page.Evaluate(focusAfterScript); err != nil {
+		return fmt.Errorf("could not move focus past the focused element: %w", err)
+	}
+	return nil
+}
+
+// ResetFocus starts keyboard navigation from the top of the page
+func ResetFocus(page playwright.Page) error {
+	if _, err := page.Evaluate(resetFocusScript, FocusOrderAttribute); err != nil {
+		return fmt.Errorf("could not reset focus: %w", err)
+	}
+	return nil
+}
+
+// FocusedElement describes the focused element as stop index, or returns
+// nil when focus is on the body or on an element already visited
+func FocusedElement(page playwright.Page, index int) (*FocusStop, error) {
+	result, err := page.Evaluate(focusInfoScript, []interface{}{FocusOrderAttribute, index})
+	if err != nil {
+		return nil, fmt.Errorf("could not read focused element: %w", err)
+	}
+	if result == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode focused element: %w", err)
+	}
+	var stop FocusStop
+	if err := json.Unmarshal(raw, &stop); err != nil {
+		return nil, fmt.Errorf("failed to parse focused element: %w", err)
+	}
+	return &stop, nil
+}
+
+// TabThrough presses Tab from the top of the page until focus leaves the
+// document or cycles back, recording each stop. An iframe is one stop and
+// tabbing continues after it. truncated reports that maxStops was reached
+// first.
+func TabThrough(page playwright.Page, maxStops int) (stops []FocusStop, truncated bool, err error) {
+	if err := ResetFocus(page); err != nil {
+		return nil, false, err
+	}
+
+	for i := 1; i <= maxStops; i++ {
+		if err := page.Keyboard().Press("Tab"); err != nil {
+			return stops, false, fmt.Errorf("could not press Tab: %w", err)
+		}
+		stop, err := FocusedElement(page, i)
+		if err != nil {
+			return stops, false, err
+		}
+		if stop == nil {
+			return stops, false, nil
+		}
+		stops = append(stops, *stop)
+		if stop.IsFrame() {
+			if err := FocusAfter(page); err != nil {
+				return stops, false, err
+			}
+		}
+	}
+	return stops, true, nil
+}
+
+// TabTo presses Tab from the current focus until an element with the given
+// accessible name is focused
+func TabTo(page playwright.Page, name string, maxStops int) (*FocusStop, error) {
+	if _, err := page.Evaluate(`(attribute) => document.querySelectorAll('[' + attribute + ']').forEach(el => el.removeAttribute(attribute))`, FocusOrderAttribute); err != nil {
+		return nil, fmt.Errorf("could not reset focus marks: %w", err)
+	}
+
+	var passed []string
+	for i := 1; i <= maxStops; i++ {
+		if err := page.Keyboard().Press("Tab"); err != nil {
+			return nil, fmt.Errorf("could not press Tab: %w", err)
+		}
+		stop, err := FocusedElement(page, i)
+		if err != nil {
+			return nil, err
+		}
+		if stop == nil {
+			break
+		}
+		if stop.Name == name {
+			return stop, nil
+		}
+		passed = append(passed, fmt.Sprintf("%q", stop.Name))
+		if stop.IsFrame() {
+			if err := FocusAfter(page); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, fmt.Errorf("no element named %q is reachable with Tab; passed %s", name, strings.Join(passed, ", "))
+}
+
+// UnreachableElements lists the visible interactive elements inside region
+// that TabThrough did not focus. found reports whether the region exists.
+func UnreachableElements(page playwright.Page, region string) (unreachable []string, found bool, err error) {
+	result, err := page.Evaluate(`([region, attribute]) => {
+		const containers = Array.from(document.querySelectorAll(region));
+		const interactive = 'a[href], button, input, select, textarea, summary, iframe, [tabindex]:not([tabindex="-1"])';
+		const visible = el => el.checkVisibility
+			? el.checkVisibility({ opacityProperty: true, visibilityProperty: true })
+			: el.offsetParent !== null;
+		const missing = [];
+		for (const container of containers) {
+			for (const el of container.querySelectorAll(interactive)) {
+				if (el.disabled || el.closest('[inert], [aria-hidden="true"]') || !visible(el)) continue;
+				if (el.hasAttribute(attribute)) continue;
+				const name = (el.getAttribute('aria-label') || el.innerText || el.textContent || '').replace(/\s+/g, ' ').trim();
+				missing.push(el.tagName.toLowerCase() + ' "' + name.slice(0, 60) + '"');
+			}
+		}
+		return { found: containers.length > 0, missing: missing };
+	}`, []interface{}{region, FocusOrderAttribute})
+	if err != nil {
+		return nil, false, fmt.Errorf("could not list interactive elements in %s: %w", region, err)
+	}
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode interactive elements: %w", err)
+	}
+	var decoded struct {
+		Found   bool     `json:"found"`
+		Missing []string `json:"missing"`
+	}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, false, fmt.Errorf("failed to parse interactive elements: %w", err)
+	}
+	return decoded.Missing, decoded.Found, nil
+}
+
+// UnnamedStops returns the stops without an accessible name
+func UnnamedStops(stops []FocusStop) []FocusStop {
+	var unnamed []FocusStop
+	for _, stop := range stops {
+		if stop.Name == "" {
+			unnamed = append(unnamed, stop)
+		}
+	}
+	return unnamed
+}
+
+// StopsWithoutFocusIndicator returns the stops where focus adds or changes
+// neither an outline nor a box-shadow, the two ways focus.css and Tailwind
+// rings draw one
+func StopsWithoutFocusIndicator(stops []FocusStop) []FocusStop {
+	var missing []FocusStop
+	for _, stop := range stops {
+		if stop.Indicator == "" {
+			missing = append(missing, stop)
+		}
+	}
+	return missing
+}
+
+// FormatFocusOrder renders the stops one per line for logs
+func FormatFocusOrder(stops []FocusStop) string {
+	lines := make([]string, len(stops))
+	for i, stop := range stops {
+		lines[i] = "  " + stop.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,63 @@
+package support
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFocusStop_String tests stops are formatted with index, role, name and selector
+func TestFocusStop_String(t *testing.T) {
+	stop := FocusStop{Index: 2, Tag: "a", Role: "link", Name: "Blog", Href: "/blog/", Selector: "nav > ul > li > a"}
+	assert.Equal(t, `2. link "Blog" (nav > ul > li > a)`, stop.String())
+}
+
+// TestFocusStop_IsSkipLink tests only in-page "Skip" links count as skip links
+func TestFocusStop_IsSkipLink(t *testing.T) {
+	assert.True(t, FocusStop{Role: "link", Name: "Skip to main content", Href: "#main-content"}.IsSkipLink())
+	assert.False(t, FocusStop{Role: "link", Name: "Blog", Href: "/blog/"}.IsSkipLink())
+	assert.False(t, FocusStop{Role: "link", Name: "Skip intro", Href: "/intro/"}.IsSkipLink())
+	assert.False(t, FocusStop{Role: "button", Name: "Skip", Href: "#main"}.IsSkipLink())
+}
+
+// TestFocusStop_IsFrame tests only iframes are stepped over as a single stop
+func TestFocusStop_IsFrame(t *testing.T) {
+	assert.True(t, FocusStop{Tag: "iframe", Role: "iframe", Name: "X Post"}.IsFrame())
+	assert.False(t, FocusStop{Tag: "a", Role: "link", Name: "Skip to main content"}.IsFrame())
+}
+
+// TestUnnamedStops tests stops without an accessible name are reported
+func TestUnnamedStops(t *testing.T) {
+	// A skip link, an unnamed carousel button and an embedded tweet
+	unnamed := UnnamedStops([]FocusStop{
+		{Index: 1, Tag: "a", Role: "link", Name: "Skip to main content", Href: "#main-content", Selector: "a.sr-only", Indicator: "outline solid 2px"},
+		{Index: 3, Tag: "button", Role: "button", Selector: "div.hero-carousel > button", Indicator: "box-shadow"},
+		{Index: 4, Tag: "iframe", Role: "iframe", Name: "X Post", Selector: "div.twitter-tweet > iframe", Indicator: "outline solid 2px"},
+	})
+	assert.Len(t, unnamed, 1)
+	assert.Equal(t, 3, unnamed[0].Index)
+}
+
+// TestStopsWithoutFocusIndicator tests stops with neither outline nor box-shadow are reported
+func TestStopsWithoutFocusIndicator(t *testing.T) {
+	// The nav link has no focus style; the others use an outline or a box-shadow
+	missing := StopsWithoutFocusIndicator([]FocusStop{
+		{Index: 1, Tag: "a", Role: "link", Name: "Skip to main content", Href: "#main-content", Selector: "a.sr-only", Indicator: "outline solid 2px"},
+		{Index: 2, Tag: "a", Role: "link", Name: "Blog", Href: "/blog/", Selector: "nav > ul > li > a"},
+		{Index: 3, Tag: "button", Role: "button", Selector: "div.hero-carousel > button", Indicator: "box-shadow"},
+		{Index: 4, Tag: "iframe", Role: "iframe", Name: "X Post", Selector: "div.twitter-tweet > iframe", Indicator: "outline solid 2px"},
+	})
+	assert.Len(t, missing, 1)
+	assert.Equal(t, "Blog", missing[0].Name)
+}
+
+// TestFormatFocusOrder tests stops are rendered one per indented line
+func TestFormatFocusOrder(t *testing.T) {
+	assert.Equal(t,
+		"  1. link \"Skip to main content\" (a.sr-only)\n  2. link \"Blog\" (nav > ul > li > a)",
+		FormatFocusOrder([]FocusStop{
+			{Index: 1, Tag: "a", Role: "link", Name: "Skip to main content", Href: "#main-content", Selector: "a.sr-only", Indicator: "outline solid 2px"},
+			{Index: 2, Tag: "a", Role: "link", Name: "Blog", Href: "/blog/", Selector: "nav > ul > li > a"},
+		}))
+	assert.Empty(t, FormatFocusOrder(nil))
+}
@@ -0,0 +1,193 @@
+package support
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// Accessible names used by layouts/partials/components/navigation.html
+const (
+	mainNavigationName    = "Main navigation"
+	desktopMenuName       = "Desktop navigation menu"
+	mobileMenuName        = "Mobile navigation menu"
+	mobileMenuToggleLabel = "Toggle mobile navigation menu"
+)
+
+// navTimeout bounds how long to wait for the navigation bar to render (ms)
+const navTimeout = 5000
+
+// NavigationMenu locates links in the site's main navigation using ARIA roles.
+// The desktop menubar is hidden below the lg breakpoint, where links live in a
+// dropdown menu opened by the hamburger toggle.
+type NavigationMenu struct {
+	page playwright.Page
+}
+
+// NewNavigationMenu creates a navigation helper for the page
+func NewNavigationMenu(page playwright.Page) *NavigationMenu {
+	return &NavigationMenu{page: page}
+}
+
+// locatorStrategy is one way of finding a navigation link
+type locatorStrategy struct {
+	description string
+	locator     playwright.Locator
+}
+
+// nav returns the main navigation landmark
+func (m *NavigationMenu) nav() playwright.Locator {
+	return m.page.GetByRole(*playwright.AriaRoleNavigation, playwright.PageGetByRoleOptions{
+		Name: mainNavigationName,
+	})
+}
+
+// toggle returns the hamburger button that opens the mobile menu
+func (m *NavigationMenu) toggle() playwright.Locator {
+	return m.nav().GetByRole(*playwright.AriaRoleButton, playwright.LocatorGetByRoleOptions{
+		Name: mobileMenuToggleLabel,
+	})
+}
+
+// IsMobile reports whether the layout shows the hamburger toggle instead of the menubar
+func (m *NavigationMenu) IsMobile() (bool, error) {
+	if err := m.nav().WaitFor(playwright.LocatorWaitForOptions{
+		State:   playwright.WaitForSelectorStateVisible,
+		Timeout: playwright.Float(navTimeout),
+	}); err != nil {
+		return false, fmt.Errorf("main navigation not visible: %w", err)
+	}
+
+	visible, err := m.toggle().IsVisible()
+	if err != nil {
+		return false, fmt.Errorf("could not check mobile menu toggle: %w", err)
+	}
+	return visible, nil
+}
+
+// mobileMenu returns the dropdown menu opened by the hamburger toggle
+func (m *NavigationMenu) mobileMenu() playwright.Locator {
+	return m.nav().GetByRole(*playwright.AriaRoleMenu, playwright.LocatorGetByRoleOptions{
+		Name: mobileMenuName,
+	})
+}
+
+// IsMobileMenuOpen reports whether the mobile dropdown menu is showing
+func (m *NavigationMenu) IsMobileMenuOpen() (bool, error) {
+	open, err := m.mobileMenu().IsVisible()
+	if err != nil {
+		return false, fmt.Errorf("could not check mobile menu: %w", err)
+	}
+	return open, nil
+}
+
+// OpenMobileMenu clicks the hamburger toggle and waits for the dropdown menu
+func (m *NavigationMenu) OpenMobileMenu() error {
+	menu := m.mobileMenu()
+
+	if open, err := menu.IsVisible(); err == nil && open {
+		return nil
+	}
+
+	if err := m.toggle().Click(); err != nil {
+		return fmt.Errorf("could not open mobile menu: %w", err)
+	}
+
+	if err := menu.WaitFor(playwright.LocatorWaitForOptions{
+		State:   playwright.WaitForSelectorStateVisible,
+		Timeout: playwright.Float(navTimeout),
+	}); err != nil {
+		return fmt.Errorf("mobile menu did not open: %w", err)
+	}
+	return nil
+}
+
+// strategies returns the locators to try for a link, most specific first
+func (m *NavigationMenu) strategies(name string, mobile bool) []locatorStrategy {
+	exact := playwright.Bool(true)
+	menuName := desktopMenuName
+	menuRole := *playwright.AriaRoleMenubar
+	if mobile {
+		menuName = mobileMenuName
+		menuRole = *playwright.AriaRoleMenu
+	}
+
+	menu := m.nav().GetByRole(menuRole, playwright.LocatorGetByRoleOptions{Name: menuName})
+
+	return []locatorStrategy{
+		{
+			description: fmt.Sprintf("%s %q > menuitem %q", menuRole, menuName, name),
+			locator:     menu.GetByRole(*playwright.AriaRoleMenuitem, playwright.LocatorGetByRoleOptions{Name: name, Exact: exact}),
+		},
+		{
+			description: fmt.Sprintf("navigation %q > link %q", mainNavigationName, name),
+			locator:     m.nav().GetByRole(*playwright.AriaRoleLink, playwright.LocatorGetByRoleOptions{Name: name, Exact: exact}),
+		},
+		{
+			description: fmt.Sprintf("navigation %q > menuitem %q", mainNavigationName, name),
+			locator:     m.nav().GetByRole(*playwright.AriaRoleMenuitem, playwright.LocatorGetByRoleOptions{Name: name, Exact: exact}),
+		},
+	}
+}
+
+// FindLink returns the visible navigation link with the given accessible name,
+// opening the mobile menu first when needed
+func (m *NavigationMenu) FindLink(name string) (playwright.Locator, error) {
+	mobile, err := m.IsMobile()
+	if err != nil {
+		return nil, err
+	}
+
+	if mobile {
+		if err := m.OpenMobileMenu(); err != nil {
+			return nil, err
+		}
+	}
+
+	var tried []string
+	for _, strategy := range m.strategies(name, mobile) {
+		// Links are already rendered once the nav is visible, so check without waiting
+		link := strategy.locator.First()
+		if visible, err := link.IsVisible(); err == nil && visible {
+			return link, nil
+		}
+		tried = append(tried, strategy.description)
+	}
+
+	layout := "desktop"
+	if mobile {
+		layout = "mobile"
+	}
+	available := "(none)"
+	if names, err := m.LinkNames(); err != nil {
+		available = fmt.Sprintf("(could not list links: %v)", err)
+	} else if len(names) > 0 {
+		available = fmt.Sprintf("%q", names)
+	}
+
+	return nil, fmt.Errorf("no visible navigation link %q in %s layout\n  tried:\n    - %s\n  links in navigation: %s",
+		name, layout, strings.Join(tried, "\n    - "), available)
+}
+
+// LinkNames lists the accessible names of visible links and menu items in the navigation
+func (m *NavigationMenu) LinkNames() ([]string, error) {
+	result, err := m.nav().Locator(`a, [role="menuitem"]`).EvaluateAll(`elements => elements
+		.filter(el => el.offsetParent !== null)
+		.map(el => (el.getAttribute('aria-label') || el.textContent || '').trim())
+		.filter(name => name.length > 0)`)
+	if err != nil {
+		return nil, fmt.Errorf("could not list navigation links: %w", err)
+	}
+
+	items, _ := result.([]interface{})
+	names := make([]string, 0, len(items))
+	seen := make(map[string]bool)
+	for _, item := range items {
+		if name, ok := item.(string); ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,102 @@
+package support
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// NotFoundMarker is the selector rendered only by the site's 404.html layout
+const NotFoundMarker = `[data-page="404"]`
+
+// Redirect is a single hop in a navigation's redirect chain
+type Redirect struct {
+	URL    string
+	Status int
+}
+
+// NavigationResult describes the main-document response of a navigation
+type NavigationResult struct {
+	URL           string
+	Status        int
+	ContentType   string
+	RedirectChain []Redirect // hops before the final response, oldest first
+}
+
+// NewNavigationResult captures status, content type and redirects from a main-document response
+func NewNavigationResult(resp playwright.Response) (*NavigationResult, error) {
+	if resp == nil {
+		return nil, fmt.Errorf("navigation produced no main-document response")
+	}
+
+	contentType, err := resp.HeaderValue("content-type")
+	if err != nil {
+		return nil, fmt.Errorf("could not read content-type: %w", err)
+	}
+
+	result := &NavigationResult{
+		URL:         resp.URL(),
+		Status:      resp.Status(),
+		ContentType: contentType,
+	}
+
+	// Walk back through the requests that redirected to this one
+	for req := resp.Request().RedirectedFrom(); req != nil; req = req.RedirectedFrom() {
+		hop := Redirect{URL: req.URL()}
+		if hopResp, err := req.Response(); err == nil && hopResp != nil {
+			hop.Status = hopResp.Status()
+		}
+		result.RedirectChain = append([]Redirect{hop}, result.RedirectChain...)
+	}
+
+	return result, nil
+}
+
+// MediaType returns the content type without parameters such as charset
+func (r *NavigationResult) MediaType() string {
+	mediaType, _, err := mime.ParseMediaType(r.ContentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(strings.Split(r.ContentType, ";")[0]))
+	}
+	return mediaType
+}
+
+// Redirected reports whether the navigation followed any redirects
+func (r *NavigationResult) Redirected() bool {
+	return len(r.RedirectChain) > 0
+}
+
+// CheckSuccess returns an error unless the response is a 2xx HTML document
+func (r *NavigationResult) CheckSuccess() error {
+	if r.Status < 200 || r.Status >= 300 {
+		return fmt.Errorf("%s returned HTTP %d", r.URL, r.Status)
+	}
+	if r.MediaType() != "text/html" {
+		return fmt.Errorf("%s returned content-type %q, expected text/html", r.URL, r.ContentType)
+	}
+	return nil
+}
+
+// DescribeRedirects renders the redirect chain for failure messages
+func (r *NavigationResult) DescribeRedirects() string {
+	if !r.Redirected() {
+		return "no redirects"
+	}
+	hops := make([]string, 0, len(r.RedirectChain)+1)
+	for _, hop := range r.RedirectChain {
+		hops = append(hops, fmt.Sprintf("%s (%d)", hop.URL, hop.Status))
+	}
+	hops = append(hops, fmt.Sprintf("%s (%d)", r.URL, r.Status))
+	return strings.Join(hops, " -> ")
+}
+
+// IsNotFoundPage reports whether the page rendered the site's 404 layout
+func IsNotFoundPage(page playwright.Page) (bool, error) {
+	count, err := page.Locator(NotFoundMarker).Count()
+	if err != nil {
+		return false, fmt.Errorf("could not check for 404 marker: %w", err)
+	}
+	return count > 0, nil
+}
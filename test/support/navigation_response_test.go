@@ -0,0 +1,62 @@
+package support
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewNavigationResult_NilResponse tests handling of navigations without a response
+func TestNewNavigationResult_NilResponse(t *testing.T) {
+	_, err := NewNavigationResult(nil)
+	assert.ErrorContains(t, err, "no main-document response")
+}
+
+// TestNavigationResult_MediaType tests content-type parameter stripping
+func TestNavigationResult_MediaType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		expected    string
+	}{
+		{"text/html; charset=utf-8", "text/html"},
+		{"TEXT/HTML", "text/html"},
+		{"application/json", "application/json"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			r := &NavigationResult{ContentType: tt.contentType}
+			assert.Equal(t, tt.expected, r.MediaType())
+		})
+	}
+}
+
+// TestNavigationResult_CheckSuccess tests status and content-type validation
+func TestNavigationResult_CheckSuccess(t *testing.T) {
+	ok := &NavigationResult{URL: "http://localhost:1313/", Status: 200, ContentType: "text/html; charset=utf-8"}
+	assert.NoError(t, ok.CheckSuccess())
+
+	missing := &NavigationResult{URL: "http://localhost:1313/nope/", Status: 404, ContentType: "text/html"}
+	assert.ErrorContains(t, missing.CheckSuccess(), "HTTP 404")
+
+	json := &NavigationResult{URL: "http://localhost:1313/index.json", Status: 200, ContentType: "application/json"}
+	assert.ErrorContains(t, json.CheckSuccess(), "expected text/html")
+}
+
+// TestNavigationResult_DescribeRedirects tests redirect chain formatting
+func TestNavigationResult_DescribeRedirects(t *testing.T) {
+	direct := &NavigationResult{URL: "http://localhost:1313/blog/", Status: 200}
+	assert.False(t, direct.Redirected())
+	assert.Equal(t, "no redirects", direct.DescribeRedirects())
+
+	redirected := &NavigationResult{
+		URL:    "http://localhost:1313/blog/",
+		Status: 200,
+		RedirectChain: []Redirect{
+			{URL: "http://localhost:1313/blog", Status: 301},
+		},
+	}
+	assert.True(t, redirected.Redirected())
+	assert.Equal(t, "http://localhost:1313/blog (301) -> http://localhost:1313/blog/ (200)", redirected.DescribeRedirects())
+}
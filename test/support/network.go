@@ -0,0 +1,317 @@
+package support
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// NetworkFixturesDir holds local stand-ins for third-party embeds and scripts
+const NetworkFixturesDir = "fixtures/network"
+
+// RouteAction is what the router does with a request
+type RouteAction string
+
+// Route actions
+const (
+	// RouteAllow lets the request reach the network
+	RouteAllow RouteAction = "allow"
+	// RouteStub answers the request from a local fixture
+	RouteStub RouteAction = "stub"
+	// RouteBlock aborts the request as blocked by the client
+	RouteBlock RouteAction = "block"
+)
+
+// HostRule decides how requests to a third-party host are handled.
+// Host matches the host itself and any subdomain of it.
+type HostRule struct {
+	Host        string
+	Action      RouteAction
+	Fixture     string // file in NetworkFixturesDir; empty stubs with 204
+	ContentType string
+}
+
+// DefaultThirdPartyRules stub the embeds used by the site's shortcodes
+// (youtube.html, x.html, substack.html) and analytics
+var DefaultThirdPartyRules = []HostRule{
+	{Host: "googletagmanager.com", Action: RouteStub, Fixture: "gtm.js", ContentType: "application/javascript"},
+	{Host: "google-analytics.com", Action: RouteStub},
+	{Host: "youtube-nocookie.com", Action: RouteStub, Fixture: "youtube-embed.html", ContentType: "text/html"},
+	{Host: "youtube.com", Action: RouteStub, Fixture: "youtube-embed.html", ContentType: "text/html"},
+	{Host: "ytimg.com", Action: RouteBlock},
+	{Host: "platform.twitter.com", Action: RouteStub, Fixture: "twitter-widgets.js", ContentType: "application/javascript"},
+	{Host: "twitter.com", Action: RouteBlock},
+	{Host: "x.com", Action: RouteBlock},
+	{Host: "substack.com", Action: RouteStub, Fixture: "substack-embed.js", ContentType: "application/javascript"},
+}
+
+// NetworkRequest is an outgoing request seen by the router
+type NetworkRequest struct {
+	PageURL      string
+	URL          string
+	Method       string
+	ResourceType string
+	Action       RouteAction
+	ThirdParty   bool
+}
+
+// Host returns the request's host name
+func (r NetworkRequest) Host() string {
+	return hostOf(r.URL)
+}
+
+// String formats the request for failure messages
+func (r NetworkRequest) String() string {
+	return fmt.Sprintf("%s %s [%s, %s]", r.Method, r.URL, r.ResourceType, r.Action)
+}
+
+// NetworkRouter routes every request in a browser context: first-party
+// requests go through, third-party ones are stubbed or blocked so scenarios
+// run offline, and everything is recorded against the page that made it
+type NetworkRouter struct {
+	// Live lets third-party requests without a rule reach the network
+	Live bool
+
+	firstParty  map[string]bool
+	rules       []HostRule
+	fixturesDir string
+
+	mu          sync.Mutex
+	requests    []NetworkRequest
+	currentPage string
+}
+
+// NewNetworkRouter creates a router treating baseURL's host and loopback as first party
+func NewNetworkRouter(baseURL string, rules []HostRule) *NetworkRouter {
+	router := &NetworkRouter{
+		firstParty: map[string]bool{
+			"localhost": true,
+			"127.0.0.1": true,
+			"::1":       true,
+		},
+		rules:       rules,
+		fixturesDir: NetworkFixturesDir,
+	}
+	if host := hostOf(baseURL); host != "" {
+		router.firstParty[host] = true
+	}
+	return router
+}
+
+// NetworkRouterFromEnv creates a router with the default rules.
+// PW_LIVE_NETWORK=1 lets unmatched third-party requests through.
+func NetworkRouterFromEnv(baseURL string) *NetworkRouter {
+	router := NewNetworkRouter(baseURL, DefaultThirdPartyRules)
+	router.Live = isTruthy(os.Getenv("PW_LIVE_NETWORK"))
+	return router
+}
+
+// Install routes all requests in the context through the router
+func (nr *NetworkRouter) Install(ctx playwright.BrowserContext) error {
+	if err := ctx.Route("**/*", nr.handle); err != nil {
+		return fmt.Errorf("failed to install network routing: %w", err)
+	}
+	return nil
+}
+
+// IsThirdParty reports whether a URL is outside the site under test
+func (nr *NetworkRouter) IsThirdParty(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		// data:, blob: and friends never leave the browser
+		return false
+	}
+	return !nr.firstParty[u.Hostname()]
+}
+
+// Decide returns the action for a URL and the rule that produced it, if any
+func (nr *NetworkRouter) Decide(rawURL string) (RouteAction, *HostRule) {
+	if !nr.IsThirdParty(rawURL) {
+		return RouteAllow, nil
+	}
+
+	host := hostOf(rawURL)
+	for i := range nr.rules {
+		if hostMatches(host, nr.rules[i].Host) {
+			return nr.rules[i].Action, &nr.rules[i]
+		}
+	}
+
+	if nr.Live {
+		return RouteAllow, nil
+	}
+	return RouteBlock, nil
+}
+
+// handle records the request and applies the matching action
+func (nr *NetworkRouter) handle(route playwright.Route) {
+	req := route.Request()
+	action, rule := nr.Decide(req.URL())
+	nr.record(req, action)
+
+	// Errors only occur once the page has closed mid-request; nothing is left to route
+	switch action {
+	case RouteStub:
+		_ = nr.fulfill(route, rule)
+	case RouteBlock:
+		_ = route.Abort("blockedbyclient")
+	default:
+		_ = route.Fallback()
+	}
+}
+
+// fulfill answers a request from the rule's fixture
+func (nr *NetworkRouter) fulfill(route playwright.Route, rule *HostRule) error {
+	if rule.Fixture == "" {
+		return route.Fulfill(playwright.RouteFulfillOptions{
+			Status: playwright.Int(204),
+		})
+	}
+
+	body, err := os.ReadFile(filepath.Join(nr.fixturesDir, rule.Fixture))
+	if err != nil {
+		// A missing fixture should surface as a failed request, not a hang
+		return route.Abort("failed")
+	}
+
+	return route.Fulfill(playwright.RouteFulfillOptions{
+		Status:      playwright.Int(200),
+		ContentType: playwright.String(rule.ContentType),
+		Body:        body,
+	})
+}
+
+// record stores a request against the page that issued it
+func (nr *NetworkRouter) record(req playwright.Request, action RouteAction) {
+	nr.mu.Lock()
+	defer nr.mu.Unlock()
+
+	if isMainFrameNavigation(req) {
+		nr.currentPage = req.URL()
+	}
+
+	nr.requests = append(nr.requests, NetworkRequest{
+		PageURL:      nr.currentPage,
+		URL:          req.URL(),
+		Method:       req.Method(),
+		ResourceType: req.ResourceType(),
+		Action:       action,
+		ThirdParty:   nr.IsThirdParty(req.URL()),
+	})
+}
+
+// isMainFrameNavigation reports whether a request loads a new top-level page
+func isMainFrameNavigation(req playwright.Request) bool {
+	if !req.IsNavigationRequest() {
+		return false
+	}
+	frame := req.Frame()
+	if frame == nil || frame.Page() == nil {
+		// Navigations issued before their frame exists are top-level
+		return true
+	}
+	return frame == frame.Page().MainFrame()
+}
+
+// Requests returns every request recorded so far
+func (nr *NetworkRouter) Requests() []NetworkRequest {
+	nr.mu.Lock()
+	defer nr.mu.Unlock()
+	return append([]NetworkRequest(nil), nr.requests...)
+}
+
+// RequestsForPage returns the requests made while pageURL was loaded
+func (nr *NetworkRouter) RequestsForPage(pageURL string) []NetworkRequest {
+	var result []NetworkRequest
+	for _, req := range nr.Requests() {
+		if req.PageURL == pageURL {
+			result = append(result, req)
+		}
+	}
+	return result
+}
+
+// RequestsMatching returns the requests whose URL matches pattern
+func (nr *NetworkRouter) RequestsMatching(pattern *regexp.Regexp) []NetworkRequest {
+	var result []NetworkRequest
+	for _, req := range nr.Requests() {
+		if pattern.MatchString(req.URL) {
+			result = append(result, req)
+		}
+	}
+	return result
+}
+
+// Reset forgets recorded requests
+func (nr *NetworkRouter) Reset() {
+	nr.mu.Lock()
+	defer nr.mu.Unlock()
+	nr.requests = nil
+}
+
+// UnexpectedThirdParty filters requests to third-party hosts outside allowed.
+// Allowed hosts also cover their subdomains.
+func UnexpectedThirdParty(requests []NetworkRequest, allowed []string) []NetworkRequest {
+	var result []NetworkRequest
+	for _, req := range requests {
+		if !req.ThirdParty {
+			continue
+		}
+		permitted := false
+		for _, host := range allowed {
+			if hostMatches(req.Host(), host) {
+				permitted = true
+				break
+			}
+		}
+		if !permitted {
+			result = append(result, req)
+		}
+	}
+	return result
+}
+
+// ThirdPartyHosts returns the distinct third-party hosts in requests
+func ThirdPartyHosts(requests []NetworkRequest) []string {
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, req := range requests {
+		if req.ThirdParty && !seen[req.Host()] {
+			seen[req.Host()] = true
+			hosts = append(hosts, req.Host())
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// FormatRequests renders requests as an indented list for error messages
+func FormatRequests(requests []NetworkRequest) string {
+	lines := make([]string, 0, len(requests))
+	for _, req := range requests {
+		lines = append(lines, "  - "+req.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// hostOf returns the lower-cased host name of a URL, or "" if it has none
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// hostMatches reports whether host is domain or one of its subdomains
+func hostMatches(host, domain string) bool {
+	domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
@@ -0,0 +1,97 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNetworkRouter_Decide tests routing decisions for first- and third-party URLs
+func TestNetworkRouter_Decide(t *testing.T) {
+	router := NewNetworkRouter("http://127.0.0.1:41234", DefaultThirdPartyRules)
+
+	tests := []struct {
+		url    string
+		action RouteAction
+	}{
+		{"http://127.0.0.1:41234/blog/", RouteAllow},
+		{"http://localhost:1313/css/main.css", RouteAllow},
+		{"data:image/png;base64,AAAA", RouteAllow},
+		{"https://www.googletagmanager.com/gtm.js?id=GTM-N9CR6KJ5", RouteStub},
+		{"https://www.youtube-nocookie.com/embed/abc?rel=0", RouteStub},
+		{"https://platform.twitter.com/widgets.js", RouteStub},
+		{"https://syndication.twitter.com/srv/timeline", RouteBlock},
+		{"https://substack.com/embedjs/embed.js", RouteStub},
+		{"https://i.ytimg.com/vi/abc/hqdefault.jpg", RouteBlock},
+		{"https://unknown.example.com/script.js", RouteBlock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			action, _ := router.Decide(tt.url)
+			assert.Equal(t, tt.action, action)
+		})
+	}
+
+	t.Run("live mode lets unmatched hosts through", func(t *testing.T) {
+		live := NewNetworkRouter("http://127.0.0.1:41234", DefaultThirdPartyRules)
+		live.Live = true
+
+		action, rule := live.Decide("https://unknown.example.com/script.js")
+		assert.Equal(t, RouteAllow, action)
+		assert.Nil(t, rule)
+
+		// Rules still apply in live mode
+		action, _ = live.Decide("https://www.googletagmanager.com/gtm.js")
+		assert.Equal(t, RouteStub, action)
+	})
+
+	t.Run("base URL host is first party", func(t *testing.T) {
+		prod := NewNetworkRouter("https://pwarnock.github.io/", nil)
+		assert.False(t, prod.IsThirdParty("https://pwarnock.github.io/about/"))
+		assert.True(t, prod.IsThirdParty("https://github.com/pwarnock"))
+	})
+}
+
+// TestDefaultThirdPartyRules_FixturesExist ensures every stub fixture is on disk
+func TestDefaultThirdPartyRules_FixturesExist(t *testing.T) {
+	for _, rule := range DefaultThirdPartyRules {
+		if rule.Fixture == "" {
+			continue
+		}
+		_, err := os.Stat(filepath.Join("..", NetworkFixturesDir, rule.Fixture))
+		assert.NoError(t, err, "fixture for %s", rule.Host)
+		assert.NotEmpty(t, rule.ContentType, "content type for %s", rule.Host)
+	}
+}
+
+// TestUnexpectedThirdParty tests filtering requests against allowed hosts
+func TestUnexpectedThirdParty(t *testing.T) {
+	requests := []NetworkRequest{
+		{URL: "http://127.0.0.1:41234/js/main.js"},
+		{URL: "https://www.youtube-nocookie.com/embed/abc", ThirdParty: true},
+		{URL: "https://www.googletagmanager.com/gtm.js", ThirdParty: true},
+		{URL: "https://platform.twitter.com/widgets.js", ThirdParty: true},
+	}
+
+	unexpected := UnexpectedThirdParty(requests, []string{"youtube-nocookie.com", "twitter.com"})
+	require.Len(t, unexpected, 1)
+	assert.Equal(t, "www.googletagmanager.com", unexpected[0].Host())
+
+	assert.Len(t, UnexpectedThirdParty(requests, nil), 3)
+	assert.Equal(t,
+		[]string{"platform.twitter.com", "www.googletagmanager.com", "www.youtube-nocookie.com"},
+		ThirdPartyHosts(requests))
+}
+
+// TestHostMatches tests domain and subdomain matching
+func TestHostMatches(t *testing.T) {
+	assert.True(t, hostMatches("x.com", "x.com"))
+	assert.True(t, hostMatches("platform.x.com", "x.com"))
+	assert.True(t, hostMatches("www.youtube.com", ".youtube.com"))
+	assert.False(t, hostMatches("notx.com", "x.com"))
+	assert.False(t, hostMatches("x.com.evil.example", "x.com"))
+}
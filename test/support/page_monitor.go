@@ -0,0 +1,174 @@
+package support
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// DefaultAllowlistPath is the allowlist of known third-party console and network noise
+const DefaultAllowlistPath = "console-allowlist.txt"
+
+// PageProblem is a JavaScript error or failed request observed on a page
+type PageProblem struct {
+	Kind    string // console, pageerror or request
+	PageURL string
+	Message string
+}
+
+// String formats the problem for failure messages
+func (p PageProblem) String() string {
+	return fmt.Sprintf("[%s] %s (on %s)", p.Kind, p.Message, p.PageURL)
+}
+
+// Allowlist holds patterns for errors that are known and accepted
+type Allowlist struct {
+	patterns []*regexp.Regexp
+}
+
+// LoadAllowlist reads one regular expression per line, ignoring blanks and # comments.
+// A missing file yields an empty allowlist.
+func LoadAllowlist(path string) (*Allowlist, error) {
+	al := &Allowlist{}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return al, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open allowlist %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		re, err := regexp.Compile(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern on line %d of %s: %w", lineNum, path, err)
+		}
+		al.patterns = append(al.patterns, re)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read allowlist %s: %w", path, err)
+	}
+
+	return al, nil
+}
+
+// Allows reports whether the text matches any allowlisted pattern
+func (al *Allowlist) Allows(text string) bool {
+	if al == nil {
+		return false
+	}
+	for _, re := range al.patterns {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// PageMonitor records console errors, uncaught exceptions and failed requests for a page
+type PageMonitor struct {
+	allowlist *Allowlist
+
+	mu       sync.Mutex
+	errors   []PageProblem
+	failures []PageProblem
+}
+
+// NewPageMonitor creates a monitor that ignores allowlisted problems
+func NewPageMonitor(allowlist *Allowlist) *PageMonitor {
+	return &PageMonitor{allowlist: allowlist}
+}
+
+// Attach subscribes to the page's console, pageerror and network events
+func (pm *PageMonitor) Attach(page playwright.Page) {
+	page.OnConsole(func(msg playwright.ConsoleMessage) {
+		if msg.Type() != "error" {
+			return
+		}
+		pm.recordError("console", page.URL(), msg.Text())
+	})
+
+	page.OnPageError(func(err error) {
+		pm.recordError("pageerror", page.URL(), err.Error())
+	})
+
+	page.OnRequestFailed(func(req playwright.Request) {
+		reason := "request failed"
+		if err := req.Failure(); err != nil {
+			reason = err.Error()
+		}
+		pm.recordFailure(page.URL(), fmt.Sprintf("%s %s: %s", req.Method(), req.URL(), reason))
+	})
+
+	page.OnResponse(func(resp playwright.Response) {
+		if resp.Status() < 400 {
+			return
+		}
+		pm.recordFailure(page.URL(), fmt.Sprintf("%s %s: HTTP %d", resp.Request().Method(), resp.URL(), resp.Status()))
+	})
+}
+
+// recordError stores a JavaScript error unless it is allowlisted
+func (pm *PageMonitor) recordError(kind, pageURL, message string) {
+	if pm.allowlist.Allows(message) {
+		return
+	}
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.errors = append(pm.errors, PageProblem{Kind: kind, PageURL: pageURL, Message: message})
+}
+
+// recordFailure stores a failed network request unless it is allowlisted
+func (pm *PageMonitor) recordFailure(pageURL, message string) {
+	if pm.allowlist.Allows(message) {
+		return
+	}
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.failures = append(pm.failures, PageProblem{Kind: "request", PageURL: pageURL, Message: message})
+}
+
+// JavaScriptErrors returns console errors and uncaught exceptions seen so far
+func (pm *PageMonitor) JavaScriptErrors() []PageProblem {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	return append([]PageProblem(nil), pm.errors...)
+}
+
+// FailedRequests returns failed or error-status requests seen so far
+func (pm *PageMonitor) FailedRequests() []PageProblem {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	return append([]PageProblem(nil), pm.failures...)
+}
+
+// Reset clears everything recorded so far
+func (pm *PageMonitor) Reset() {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.errors = nil
+	pm.failures = nil
+}
+
+// FormatProblems renders problems as an indented list for error messages
+func FormatProblems(problems []PageProblem) string {
+	lines := make([]string, 0, len(problems))
+	for _, p := range problems {
+		lines = append(lines, "  - "+p.String())
+	}
+	return strings.Join(lines, "\n")
+}
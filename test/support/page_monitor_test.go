@@ -0,0 +1,85 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadAllowlist tests allowlist parsing
+func TestLoadAllowlist(t *testing.T) {
+	t.Run("missing file yields empty allowlist", func(t *testing.T) {
+		al, err := LoadAllowlist(filepath.Join(t.TempDir(), "missing.txt"))
+		require.NoError(t, err)
+		assert.False(t, al.Allows("anything"))
+	})
+
+	t.Run("skips comments and blank lines", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "allowlist.txt")
+		content := "# comment\n\ngoogletagmanager\\.com\n  youtube\\.com  \n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+		al, err := LoadAllowlist(path)
+		require.NoError(t, err)
+		assert.True(t, al.Allows("GET https://www.googletagmanager.com/gtm.js: net::ERR_BLOCKED"))
+		assert.True(t, al.Allows("Refused to frame https://www.youtube.com/embed/x"))
+		assert.False(t, al.Allows("Uncaught TypeError: Alpine is undefined"))
+	})
+
+	t.Run("rejects invalid patterns", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "allowlist.txt")
+		require.NoError(t, os.WriteFile(path, []byte("valid\n(unclosed\n"), 0o644))
+
+		_, err := LoadAllowlist(path)
+		assert.ErrorContains(t, err, "line 2")
+	})
+
+	t.Run("repo allowlist is valid", func(t *testing.T) {
+		_, err := LoadAllowlist(filepath.Join("..", DefaultAllowlistPath))
+		assert.NoError(t, err)
+	})
+
+	t.Run("repo allowlist matches hosts, not substrings", func(t *testing.T) {
+		al, err := LoadAllowlist(filepath.Join("..", DefaultAllowlistPath))
+		require.NoError(t, err)
+		assert.True(t, al.Allows("GET https://platform.twitter.com/widgets.js: net::ERR_FAILED"))
+		assert.True(t, al.Allows("GET https://x.com/i/api: net::ERR_FAILED"))
+		assert.False(t, al.Allows("GET https://box.com/api: net::ERR_FAILED"))
+		assert.False(t, al.Allows("Uncaught Error: fox.com widget failed"))
+	})
+}
+
+// TestPageMonitor_Record tests that problems are recorded and allowlisted ones dropped
+func TestPageMonitor_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allowlist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("googletagmanager\\.com\n"), 0o644))
+	al, err := LoadAllowlist(path)
+	require.NoError(t, err)
+
+	pm := NewPageMonitor(al)
+	pm.recordError("pageerror", "http://localhost:1313/", "Uncaught TypeError: x is undefined")
+	pm.recordError("console", "http://localhost:1313/", "Failed to load https://www.googletagmanager.com/gtm.js")
+	pm.recordFailure("http://localhost:1313/", "GET http://localhost:1313/missing.js: HTTP 404")
+	pm.recordFailure("http://localhost:1313/", "GET https://www.googletagmanager.com/gtm.js: net::ERR_FAILED")
+
+	errs := pm.JavaScriptErrors()
+	require.Len(t, errs, 1)
+	assert.Equal(t, "pageerror", errs[0].Kind)
+
+	failures := pm.FailedRequests()
+	require.Len(t, failures, 1)
+	assert.Contains(t, FormatProblems(failures), "missing.js: HTTP 404")
+
+	pm.Reset()
+	assert.Empty(t, pm.JavaScriptErrors())
+	assert.Empty(t, pm.FailedRequests())
+}
+
+// TestAllowlist_Nil tests that a nil allowlist allows nothing
+func TestAllowlist_Nil(t *testing.T) {
+	var al *Allowlist
+	assert.False(t, al.Allows("anything"))
+}
@@ -0,0 +1,287 @@
+package support
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// ArtifactsDir is where failure artifacts (screenshots, traces, videos) are written
+const ArtifactsDir = "test-results"
+
+// RetentionPolicy controls which recordings are kept after a scenario finishes
+type RetentionPolicy string
+
+const (
+	// RetainOnFailure keeps recordings only for failed scenarios
+	RetainOnFailure RetentionPolicy = "on-failure"
+	// RetainAlways keeps recordings for every scenario
+	RetainAlways RetentionPolicy = "always"
+	// RetainNever discards recordings even when a scenario fails
+	RetainNever RetentionPolicy = "never"
+)
+
+// RecordingOptions configures Playwright tracing and video recording for a scenario
+type RecordingOptions struct {
+	Trace     bool
+	Video     bool
+	Retention RetentionPolicy
+	OutputDir string
+}
+
+// Enabled reports whether any recording is requested
+func (o RecordingOptions) Enabled() bool {
+	return o.Trace || o.Video
+}
+
+// RecordingOptionsFromEnv reads recording options from the environment
+//
+//	PW_TRACE=1          record a Playwright trace (screenshots, snapshots, sources)
+//	PW_VIDEO=1          record a video of the browser context
+//	PW_RETAIN=<policy>  on-failure (default), always or never
+//	PW_ARTIFACTS_DIR    output directory (default test-results)
+func RecordingOptionsFromEnv() RecordingOptions {
+	opts := RecordingOptions{
+		Trace:     isTruthy(os.Getenv("PW_TRACE")),
+		Video:     isTruthy(os.Getenv("PW_VIDEO")),
+		Retention: RetainOnFailure,
+		OutputDir: ArtifactsDir,
+	}
+
+	if policy, err := ParseRetentionPolicy(os.Getenv("PW_RETAIN")); err == nil {
+		opts.Retention = policy
+	} else {
+		fmt.Printf("Warning: %v, using %s\n", err, RetainOnFailure)
+	}
+
+	if dir := os.Getenv("PW_ARTIFACTS_DIR"); dir != "" {
+		opts.OutputDir = dir
+	}
+
+	return opts
+}
+
+// WithTags enables recording for scenarios tagged @trace or @video
+func (o RecordingOptions) WithTags(tags []string) RecordingOptions {
+	for _, tag := range tags {
+		switch tag {
+		case "@trace":
+			o.Trace = true
+			o.Video = true
+		case "@video":
+			o.Video = true
+		}
+	}
+	return o
+}
+
+// ParseRetentionPolicy parses a retention policy name, defaulting to on-failure
+func ParseRetentionPolicy(value string) (RetentionPolicy, error) {
+	switch RetentionPolicy(strings.ToLower(strings.TrimSpace(value))) {
+	case "", RetainOnFailure:
+		return RetainOnFailure, nil
+	case RetainAlways:
+		return RetainAlways, nil
+	case RetainNever:
+		return RetainNever, nil
+	default:
+		return RetainOnFailure, fmt.Errorf("unknown retention policy %q", value)
+	}
+}
+
+// shouldRetain decides whether recordings are kept for a scenario outcome
+func (o RecordingOptions) shouldRetain(failed bool) bool {
+	switch o.Retention {
+	case RetainAlways:
+		return true
+	case RetainNever:
+		return false
+	default:
+		return failed
+	}
+}
+
+// Recorder manages tracing and video for a single scenario
+type Recorder struct {
+	opts     RecordingOptions
+	context  playwright.BrowserContext
+	page     playwright.Page
+	owned    bool // context was created by the recorder and must be closed by it
+	tracing  bool
+	scenario string
+	id       string // pickle ID, distinct for each Scenario Outline example row
+}
+
+// NewRecorder creates a recorder for the named scenario. id keeps the
+// artifacts of Scenario Outline rows, which share a name, apart.
+func NewRecorder(opts RecordingOptions, scenario, id string) *Recorder {
+	return &Recorder{
+		opts:     opts,
+		scenario: scenario,
+		id:       id,
+	}
+}
+
+// Attach starts recording for the given page and returns the page steps should use.
+// Video can only be captured by a context created with recordVideo, so when video is
+// requested a dedicated context is opened on the same browser and its page returned.
+func (r *Recorder) Attach(page playwright.Page) (playwright.Page, error) {
+	if !r.opts.Enabled() || page == nil {
+		return page, nil
+	}
+
+	r.page = page
+	r.context = page.Context()
+
+	if r.opts.Video {
+		browser := r.context.Browser()
+		if browser == nil {
+			return page, fmt.Errorf("cannot record video: page has no owning browser")
+		}
+
+		contextOpts := playwright.BrowserNewContextOptions{
+			RecordVideo: &playwright.RecordVideo{
+				Dir: filepath.Join(r.opts.OutputDir, "videos"),
+			},
+		}
+		if viewport := page.ViewportSize(); viewport != nil {
+			contextOpts.Viewport = viewport
+			contextOpts.RecordVideo.Size = &playwright.Size{
+				Width:  viewport.Width,
+				Height: viewport.Height,
+			}
+		}
+
+		recorded, err := browser.NewContext(contextOpts)
+		if err != nil {
+			return page, fmt.Errorf("failed to create recording context: %w", err)
+		}
+		recordedPage, err := recorded.NewPage()
+		if err != nil {
+			recorded.Close()
+			return page, fmt.Errorf("failed to open recording page: %w", err)
+		}
+
+		r.context = recorded
+		r.page = recordedPage
+		r.owned = true
+	}
+
+	if r.opts.Trace {
+		err := r.context.Tracing().Start(playwright.TracingStartOptions{
+			Title:       playwright.String(r.scenario),
+			Screenshots: playwright.Bool(true),
+			Snapshots:   playwright.Bool(true),
+			Sources:     playwright.Bool(true),
+		})
+		if err != nil {
+			return r.page, fmt.Errorf("failed to start tracing: %w", err)
+		}
+		r.tracing = true
+	}
+
+	return r.page, nil
+}
+
+// Stop finishes recording and keeps or discards artifacts according to the retention policy.
+// It returns the paths of the artifacts that were kept.
+func (r *Recorder) Stop(failed bool) ([]string, error) {
+	if r.context == nil {
+		return nil, nil
+	}
+
+	retain := r.opts.shouldRetain(failed)
+	base := filepath.Join(r.opts.OutputDir, artifactName(r.scenario, r.id))
+	var kept []string
+	var errs []string
+
+	if retain {
+		if err := os.MkdirAll(r.opts.OutputDir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create artifacts directory: %w", err)
+		}
+	}
+
+	if r.tracing {
+		var err error
+		if retain {
+			err = r.context.Tracing().Stop(base + "-trace.zip")
+		} else {
+			err = r.context.Tracing().Stop()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("stop tracing: %v", err))
+		} else if retain {
+			kept = append(kept, base+"-trace.zip")
+		}
+		r.tracing = false
+	}
+
+	if r.owned {
+		// The library screenshots its own page, which is idle while a recording page is in use
+		if failed && retain {
+			if _, err := r.page.Screenshot(playwright.PageScreenshotOptions{
+				Path:     playwright.String(base + ".png"),
+				FullPage: playwright.Bool(true),
+			}); err != nil {
+				errs = append(errs, fmt.Sprintf("screenshot: %v", err))
+			} else {
+				kept = append(kept, base+".png")
+			}
+		}
+
+		video := r.page.Video()
+		// The video is only flushed to disk once its context is closed
+		if err := r.context.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("close recording context: %v", err))
+		}
+		if video != nil {
+			if retain {
+				if err := video.SaveAs(base + ".webm"); err != nil {
+					errs = append(errs, fmt.Sprintf("save video: %v", err))
+				} else {
+					kept = append(kept, base+".webm")
+				}
+			}
+			if err := video.Delete(); err != nil {
+				errs = append(errs, fmt.Sprintf("delete video: %v", err))
+			}
+		}
+	}
+
+	r.context = nil
+	r.page = nil
+	r.owned = false
+
+	if len(errs) > 0 {
+		return kept, fmt.Errorf("recording cleanup failed: %s", strings.Join(errs, "; "))
+	}
+	return kept, nil
+}
+
+var unsafeArtifactChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// artifactName turns a scenario name and ID into a safe file name
+func artifactName(scenario, id string) string {
+	name := strings.Trim(unsafeArtifactChars.ReplaceAllString(scenario, "-"), "-")
+	if name == "" {
+		name = "scenario"
+	}
+	if id = strings.Trim(unsafeArtifactChars.ReplaceAllString(id, "-"), "-"); id != "" {
+		name += "-" + id
+	}
+	return strings.ToLower(name)
+}
+
+// isTruthy reports whether an environment value enables a feature
+func isTruthy(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
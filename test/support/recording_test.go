@@ -0,0 +1,107 @@
+package support
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseRetentionPolicy tests retention policy parsing
+func TestParseRetentionPolicy(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected RetentionPolicy
+		wantErr  bool
+	}{
+		{"", RetainOnFailure, false},
+		{"on-failure", RetainOnFailure, false},
+		{"ALWAYS", RetainAlways, false},
+		{" never ", RetainNever, false},
+		{"sometimes", RetainOnFailure, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			policy, err := ParseRetentionPolicy(tt.value)
+			assert.Equal(t, tt.expected, policy)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestRecordingOptionsFromEnv tests environment-driven configuration
+func TestRecordingOptionsFromEnv(t *testing.T) {
+	t.Run("defaults to disabled", func(t *testing.T) {
+		t.Setenv("PW_TRACE", "")
+		t.Setenv("PW_VIDEO", "")
+		t.Setenv("PW_RETAIN", "")
+		t.Setenv("PW_ARTIFACTS_DIR", "")
+
+		opts := RecordingOptionsFromEnv()
+		assert.False(t, opts.Enabled())
+		assert.Equal(t, RetainOnFailure, opts.Retention)
+		assert.Equal(t, ArtifactsDir, opts.OutputDir)
+	})
+
+	t.Run("reads env values", func(t *testing.T) {
+		t.Setenv("PW_TRACE", "1")
+		t.Setenv("PW_VIDEO", "true")
+		t.Setenv("PW_RETAIN", "always")
+		t.Setenv("PW_ARTIFACTS_DIR", "/tmp/artifacts")
+
+		opts := RecordingOptionsFromEnv()
+		assert.True(t, opts.Trace)
+		assert.True(t, opts.Video)
+		assert.Equal(t, RetainAlways, opts.Retention)
+		assert.Equal(t, "/tmp/artifacts", opts.OutputDir)
+	})
+}
+
+// TestRecordingOptions_WithTags tests tag-driven enabling
+func TestRecordingOptions_WithTags(t *testing.T) {
+	opts := RecordingOptions{}.WithTags([]string{"@smoke"})
+	assert.False(t, opts.Enabled())
+
+	opts = RecordingOptions{}.WithTags([]string{"@video"})
+	assert.True(t, opts.Video)
+	assert.False(t, opts.Trace)
+
+	opts = RecordingOptions{}.WithTags([]string{"@trace"})
+	assert.True(t, opts.Trace)
+	assert.True(t, opts.Video)
+}
+
+// TestRecordingOptions_ShouldRetain tests retention decisions
+func TestRecordingOptions_ShouldRetain(t *testing.T) {
+	assert.True(t, RecordingOptions{Retention: RetainOnFailure}.shouldRetain(true))
+	assert.False(t, RecordingOptions{Retention: RetainOnFailure}.shouldRetain(false))
+	assert.True(t, RecordingOptions{Retention: RetainAlways}.shouldRetain(false))
+	assert.False(t, RecordingOptions{Retention: RetainNever}.shouldRetain(true))
+}
+
+// TestRecorder_StopWithoutAttach tests that an unattached recorder is a no-op
+func TestRecorder_StopWithoutAttach(t *testing.T) {
+	r := NewRecorder(RecordingOptions{Trace: true}, "Navigate to blog page", "7")
+
+	kept, err := r.Stop(true)
+	assert.NoError(t, err)
+	assert.Empty(t, kept)
+}
+
+// TestArtifactName tests scenario name sanitizing
+func TestArtifactName(t *testing.T) {
+	assert.Equal(t, "navigate-to-blog-page", artifactName("Navigate to blog page", ""))
+	assert.Equal(t, "homepage-a11y", artifactName("Homepage: a11y!", ""))
+	assert.Equal(t, "scenario", artifactName("!!!", ""))
+	assert.Equal(t, "scenario-3", artifactName("!!!", "3"))
+
+	// Scenario Outline rows share a name but not a pickle ID
+	first := artifactName("<environment> build publishes no unpublished content", "12")
+	second := artifactName("<environment> build publishes no unpublished content", "13")
+	assert.Equal(t, "environment-build-publishes-no-unpublished-content-12", first)
+	assert.NotEqual(t, first, second)
+}
@@ -0,0 +1,133 @@
+package support
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// RobotsPath is where static/robots.txt ends up in a build
+const RobotsPath = "robots.txt"
+
+// RobotsRule is one Allow or Disallow line
+type RobotsRule struct {
+	Allow   bool
+	Pattern string
+}
+
+// RobotsGroup is the rules for one or more consecutive User-agent lines
+type RobotsGroup struct {
+	Agents []string
+	Rules  []RobotsRule
+}
+
+// Robots is a parsed robots.txt
+type Robots struct {
+	Groups   []RobotsGroup
+	Sitemaps []string
+}
+
+// LoadRobots reads robots.txt from a build
+func LoadRobots(site *BuiltSite) (*Robots, error) {
+	data, err := os.ReadFile(filepath.Join(site.Dir, RobotsPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", RobotsPath, err)
+	}
+	return ParseRobots(string(data)), nil
+}
+
+// ParseRobots parses robots.txt per RFC 9309. Sitemap lines apply to the
+// whole file; unknown fields such as Crawl-delay are ignored.
+func ParseRobots(content string) *Robots {
+	robots := &Robots{}
+	var group *RobotsGroup
+	lastWasAgent := false
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		field = strings.ToLower(strings.TrimSpace(field))
+		value = strings.TrimSpace(value)
+
+		switch field {
+		case "user-agent":
+			// Consecutive User-agent lines share one group
+			if group == nil || !lastWasAgent {
+				robots.Groups = append(robots.Groups, RobotsGroup{})
+				group = &robots.Groups[len(robots.Groups)-1]
+			}
+			group.Agents = append(group.Agents, strings.ToLower(value))
+			lastWasAgent = true
+			continue
+		case "allow", "disallow":
+			// An empty Disallow allows everything and adds no rule
+			if group != nil && value != "" {
+				group.Rules = append(group.Rules, RobotsRule{Allow: field == "allow", Pattern: value})
+			}
+		case "sitemap":
+			robots.Sitemaps = append(robots.Sitemaps, value)
+		}
+		lastWasAgent = false
+	}
+
+	return robots
+}
+
+// group returns the group that applies to agent: an exact match, else *
+func (r *Robots) group(agent string) *RobotsGroup {
+	agent = strings.ToLower(agent)
+	var fallback *RobotsGroup
+	for i := range r.Groups {
+		for _, a := range r.Groups[i].Agents {
+			if a == agent {
+				return &r.Groups[i]
+			}
+			if a == "*" && fallback == nil {
+				fallback = &r.Groups[i]
+			}
+		}
+	}
+	return fallback
+}
+
+// Allowed reports whether agent may crawl urlPath. The longest matching rule
+// wins and Allow wins a tie, as Google and RFC 9309 specify.
+func (r *Robots) Allowed(agent, urlPath string) bool {
+	group := r.group(agent)
+	if group == nil {
+		return true
+	}
+
+	allowed, longest := true, -1
+	for _, rule := range group.Rules {
+		if !robotsPatternMatches(rule.Pattern, urlPath) {
+			continue
+		}
+		if len(rule.Pattern) > longest || (len(rule.Pattern) == longest && rule.Allow) {
+			allowed, longest = rule.Allow, len(rule.Pattern)
+		}
+	}
+	return allowed
+}
+
+// robotsPatternMatches matches a path prefix pattern supporting * and a trailing $
+func robotsPatternMatches(pattern, urlPath string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr).MatchString(urlPath)
+}
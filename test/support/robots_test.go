@@ -0,0 +1,51 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseRobots_Groups tests agent grouping, sitemaps and precedence
+func TestParseRobots_Groups(t *testing.T) {
+	robots := ParseRobots(`User-agent: *
+Allow: /
+Disallow: /admin/
+Disallow: /*.json$
+Allow: /admin/public/
+
+Sitemap: https://example.com/sitemap.xml
+
+User-agent: AhrefsBot
+User-agent: MJ12bot
+Disallow: /
+Crawl-delay: 1
+`)
+
+	require.Len(t, robots.Groups, 2)
+	assert.Equal(t, []string{"ahrefsbot", "mj12bot"}, robots.Groups[1].Agents)
+	assert.Equal(t, []string{"https://example.com/sitemap.xml"}, robots.Sitemaps)
+
+	assert.True(t, robots.Allowed("*", "/blog/"))
+	assert.False(t, robots.Allowed("*", "/admin/settings/"))
+	assert.True(t, robots.Allowed("*", "/admin/public/"), "longer Allow wins")
+	assert.False(t, robots.Allowed("*", "/index.json"))
+	assert.True(t, robots.Allowed("*", "/index.json/"), "$ anchors the pattern")
+	assert.False(t, robots.Allowed("MJ12bot", "/blog/"))
+	assert.True(t, robots.Allowed("Googlebot", "/blog/"), "falls back to *")
+}
+
+// TestSiteRobots tests the hand-written robots.txt keeps sections crawlable
+func TestSiteRobots(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", SiteDir, "static", RobotsPath))
+	require.NoError(t, err)
+	robots := ParseRobots(string(data))
+
+	assert.Contains(t, robots.Sitemaps, "https://peterwarnock.com/sitemap.xml")
+	for _, section := range []string{"/", "/blog/", "/tools/", "/portfolio/"} {
+		assert.True(t, robots.Allowed("*", section), section)
+	}
+}
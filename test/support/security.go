@@ -0,0 +1,295 @@
+package support
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SecurityTxtPaths are checked in order; RFC 9116 makes .well-known canonical
+var SecurityTxtPaths = []string{".well-known/security.txt", "security.txt"}
+
+// MetaExpectation is a policy meta tag partials/security-headers.html must emit
+type MetaExpectation struct {
+	Name      string   // name attribute, or
+	HTTPEquiv string   // http-equiv attribute
+	Contains  []string // required substrings of content; empty means any value
+}
+
+// Key names the tag in failure messages
+func (m MetaExpectation) Key() string {
+	if m.HTTPEquiv != "" {
+		return fmt.Sprintf(`meta[http-equiv=%q]`, m.HTTPEquiv)
+	}
+	return fmt.Sprintf(`meta[name=%q]`, m.Name)
+}
+
+// ExpectedSecurityMeta mirrors partials/security-headers.html. theme-color is
+// rewritten per theme at runtime, so only its presence is checked.
+var ExpectedSecurityMeta = []MetaExpectation{
+	{Name: "referrer", Contains: []string{"strict-origin-when-cross-origin"}},
+	{HTTPEquiv: "Permissions-Policy", Contains: []string{"camera=()", "microphone=()", "geolocation=()", "payment=()"}},
+	{Name: "theme-color"},
+	{HTTPEquiv: "X-Content-Type-Options", Contains: []string{"nosniff"}},
+}
+
+// CheckSecurityMeta returns problems with the page's policy meta tags
+func CheckSecurityMeta(document string, expected []MetaExpectation) []string {
+	metas := FindTags(document, "meta")
+
+	var problems []string
+	for _, exp := range expected {
+		tag, ok := findMeta(metas, exp)
+		if !ok {
+			problems = append(problems, exp.Key()+" is missing")
+			continue
+		}
+		content := tag.Attr("content")
+		for _, want := range exp.Contains {
+			if !strings.Contains(content, want) {
+				problems = append(problems, fmt.Sprintf("%s content %q lacks %q", exp.Key(), content, want))
+			}
+		}
+	}
+	return problems
+}
+
+// findMeta returns the first meta tag matching an expectation
+func findMeta(metas []HTMLTag, exp MetaExpectation) (HTMLTag, bool) {
+	for _, tag := range metas {
+		if exp.HTTPEquiv != "" && strings.EqualFold(tag.Attr("http-equiv"), exp.HTTPEquiv) {
+			return tag, true
+		}
+		if exp.Name != "" && strings.EqualFold(tag.Attr("name"), exp.Name) {
+			return tag, true
+		}
+	}
+	return HTMLTag{}, false
+}
+
+// subresourceAttrs are the attributes that make the browser fetch something
+// without a navigation, per element
+var subresourceAttrs = map[string][]string{
+	"script": {"src"},
+	"img":    {"src", "srcset"},
+	"source": {"src", "srcset"},
+	"iframe": {"src"},
+	"video":  {"src", "poster"},
+	"audio":  {"src"},
+	"track":  {"src"},
+	"embed":  {"src"},
+	"object": {"data"},
+	"form":   {"action"},
+}
+
+// cssURLPattern finds url(...) references in inline styles
+var cssURLPattern = regexp.MustCompile(`url\(\s*['"]?(http://[^'")\s]+)`)
+
+// FindMixedContent returns http:// subresources referenced by a page.
+// Plain links are navigations, not mixed content, so <a href> is ignored.
+func FindMixedContent(document string) []string {
+	var found []string
+	for _, tag := range ParseTags(document) {
+		attrs := subresourceAttrs[tag.Name]
+		if tag.Name == "link" && !isNavigationLink(tag.Attr("rel")) {
+			attrs = []string{"href"}
+		}
+
+		for _, attr := range attrs {
+			for _, ref := range splitSrcset(tag.Attr(attr)) {
+				if isInsecureURL(ref) {
+					found = append(found, fmt.Sprintf("line %d: <%s %s=%q>", tag.Line, tag.Name, attr, ref))
+				}
+			}
+		}
+
+		for _, m := range cssURLPattern.FindAllStringSubmatch(tag.Attr("style")+tag.Text, -1) {
+			if isInsecureURL(m[1]) {
+				found = append(found, fmt.Sprintf("line %d: <%s> url(%s)", tag.Line, tag.Name, m[1]))
+			}
+		}
+	}
+	return found
+}
+
+// isNavigationLink reports whether a <link rel> only describes a related page
+func isNavigationLink(rel string) bool {
+	for _, r := range strings.Fields(strings.ToLower(rel)) {
+		switch r {
+		case "canonical", "alternate", "prev", "next", "author", "license", "me", "shortlink":
+			continue
+		default:
+			return false
+		}
+	}
+	return rel != ""
+}
+
+// splitSrcset returns the URLs in a src or srcset value
+func splitSrcset(value string) []string {
+	var refs []string
+	for _, candidate := range strings.Split(value, ",") {
+		if fields := strings.Fields(candidate); len(fields) > 0 {
+			refs = append(refs, fields[0])
+		}
+	}
+	return refs
+}
+
+// isInsecureURL reports whether a reference is loaded over plain http
+func isInsecureURL(ref string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(ref)), "http://")
+}
+
+// dataScriptTypes are script types the browser never executes
+var dataScriptTypes = map[string]bool{
+	"application/ld+json": true,
+	"application/json":    true,
+	"importmap":           true,
+	"text/template":       true,
+	"text/x-template":     true,
+}
+
+// InlineScriptsWithoutNonce returns executable inline scripts lacking a nonce
+func InlineScriptsWithoutNonce(document string) []string {
+	var found []string
+	for _, tag := range FindTags(document, "script") {
+		if tag.HasAttr("src") || tag.HasAttr("nonce") {
+			continue
+		}
+		if dataScriptTypes[strings.ToLower(tag.Attr("type"))] || strings.TrimSpace(tag.Text) == "" {
+			continue
+		}
+		found = append(found, fmt.Sprintf("line %d: %s", tag.Line, snippet(tag.Text, 60)))
+	}
+	return found
+}
+
+// BlankTargetsWithoutNoopener returns target="_blank" links that leave
+// window.opener exposed. rel="noreferrer" implies noopener and is accepted.
+func BlankTargetsWithoutNoopener(document string) []string {
+	var found []string
+	for _, tag := range ParseTags(document) {
+		if (tag.Name != "a" && tag.Name != "area" && tag.Name != "form") ||
+			!strings.EqualFold(tag.Attr("target"), "_blank") {
+			continue
+		}
+
+		rel := strings.Fields(strings.ToLower(tag.Attr("rel")))
+		if containsString(rel, "noopener") || containsString(rel, "noreferrer") {
+			continue
+		}
+		found = append(found, fmt.Sprintf("line %d: %s", tag.Line, snippet(tag.Raw, 100)))
+	}
+	return found
+}
+
+// snippet collapses whitespace and truncates text for messages
+func snippet(text string, max int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) > max {
+		return text[:max] + "…"
+	}
+	return text
+}
+
+// containsString reports whether list contains value
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SecurityTxt is a parsed RFC 9116 security.txt
+type SecurityTxt struct {
+	Path     string
+	Fields   map[string][]string // keyed by lower-cased field name
+	Problems []string            // lines that are neither fields nor comments
+}
+
+// securityTxtField matches "Field-Name: value"
+var securityTxtField = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*):\s*(.*)$`)
+
+// FindSecurityTxt locates security.txt in a build directory
+func FindSecurityTxt(buildDir string) (string, error) {
+	for _, rel := range SecurityTxtPaths {
+		path := filepath.Join(buildDir, filepath.FromSlash(rel))
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no security.txt found (looked for %s)", strings.Join(SecurityTxtPaths, ", "))
+}
+
+// LoadSecurityTxt reads and parses a security.txt file
+func LoadSecurityTxt(path string) (*SecurityTxt, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	st := &SecurityTxt{Path: path, Fields: make(map[string][]string)}
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m := securityTxtField.FindStringSubmatch(line)
+		if m == nil {
+			st.Problems = append(st.Problems, fmt.Sprintf("line %d is not a field or comment: %s", lineNum, snippet(line, 60)))
+			continue
+		}
+		name := strings.ToLower(m[1])
+		st.Fields[name] = append(st.Fields[name], strings.TrimSpace(m[2]))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return st, nil
+}
+
+// Validate returns every way the file falls short of RFC 9116 at time now
+func (st *SecurityTxt) Validate(now time.Time) []string {
+	problems := append([]string(nil), st.Problems...)
+
+	contacts := st.Fields["contact"]
+	if len(contacts) == 0 {
+		problems = append(problems, "Contact field is required")
+	}
+	for _, contact := range contacts {
+		u, err := url.Parse(contact)
+		if err != nil || (u.Scheme != "mailto" && u.Scheme != "https" && u.Scheme != "tel") {
+			problems = append(problems, fmt.Sprintf("Contact %q must be a mailto:, https: or tel: URI", contact))
+		}
+	}
+
+	expires := st.Fields["expires"]
+	switch {
+	case len(expires) == 0:
+		problems = append(problems, "Expires field is required")
+	case len(expires) > 1:
+		problems = append(problems, "Expires must appear only once")
+	default:
+		at, err := time.Parse(time.RFC3339, expires[0])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("Expires %q is not an RFC 3339 timestamp", expires[0]))
+		} else if !at.After(now) {
+			problems = append(problems, fmt.Sprintf("Expires %s is in the past", expires[0]))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,121 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckSecurityMeta tests policy meta tag detection
+func TestCheckSecurityMeta(t *testing.T) {
+	complete := `<head>
+<meta name="referrer" content="strict-origin-when-cross-origin">
+<meta name=theme-color content=#ffffff>
+<meta http-equiv="Permissions-Policy" content="camera=(), microphone=(), geolocation=(), payment=(), usb=()">
+<meta http-equiv="X-Content-Type-Options" content="nosniff">
+</head>`
+	assert.Empty(t, CheckSecurityMeta(complete, ExpectedSecurityMeta))
+
+	problems := CheckSecurityMeta(`<head><meta name="referrer" content="unsafe-url"></head>`, ExpectedSecurityMeta)
+	assert.Contains(t, problems, `meta[name="referrer"] content "unsafe-url" lacks "strict-origin-when-cross-origin"`)
+	assert.Contains(t, problems, `meta[http-equiv="Permissions-Policy"] is missing`)
+	assert.Contains(t, problems, `meta[name="theme-color"] is missing`)
+}
+
+// TestCheckSecurityMeta_Partial checks the expectations match the site partial
+func TestCheckSecurityMeta_Partial(t *testing.T) {
+	partial, err := os.ReadFile(filepath.Join("..", SiteDir, "layouts", "partials", "security-headers.html"))
+	require.NoError(t, err)
+	assert.Empty(t, CheckSecurityMeta(string(partial), ExpectedSecurityMeta))
+}
+
+// TestFindMixedContent tests insecure subresource detection
+func TestFindMixedContent(t *testing.T) {
+	doc := `<a href="http://example.com/">plain link is fine</a>
+<link rel="canonical" href="http://example.com/">
+<link rel="stylesheet" href="http://cdn.example.com/site.css">
+<script src="http://cdn.example.com/app.js"></script>
+<img src="/local.png" srcset="/a.png 1x, http://cdn.example.com/b.png 2x">
+<div style="background: url('http://cdn.example.com/bg.png')"></div>
+<iframe src="https://www.youtube-nocookie.com/embed/x"></iframe>`
+
+	found := FindMixedContent(doc)
+	require.Len(t, found, 4)
+	assert.Contains(t, found[0], "site.css")
+	assert.Contains(t, found[1], "app.js")
+	assert.Contains(t, found[2], "b.png")
+	assert.Contains(t, found[3], "bg.png")
+}
+
+// TestInlineScriptsWithoutNonce tests executable inline script detection
+func TestInlineScriptsWithoutNonce(t *testing.T) {
+	doc := `<script>window.a = 1</script>
+<script nonce="abc">window.b = 2</script>
+<script src="/app.js"></script>
+<script type="application/ld+json">{"@type":"Person"}</script>
+<script> </script>`
+
+	found := InlineScriptsWithoutNonce(doc)
+	require.Len(t, found, 1)
+	assert.Equal(t, "line 1: window.a = 1", found[0])
+}
+
+// TestBlankTargetsWithoutNoopener tests reverse tabnabbing detection
+func TestBlankTargetsWithoutNoopener(t *testing.T) {
+	doc := `<a href="https://a.example" target="_blank">bad</a>
+<a href="https://b.example" target="_blank" rel="noopener">ok</a>
+<a href="https://c.example" target=_blank rel="external noreferrer">ok</a>
+<a href="https://d.example" target="_self">ok</a>
+<a href="https://e.example" target="_BLANK" rel="nofollow">bad</a>`
+
+	found := BlankTargetsWithoutNoopener(doc)
+	require.Len(t, found, 2)
+	assert.Contains(t, found[0], "a.example")
+	assert.Contains(t, found[1], "e.example")
+}
+
+// TestSecurityTxt tests RFC 9116 parsing and validation
+func TestSecurityTxt(t *testing.T) {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "security.txt")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	t.Run("valid file", func(t *testing.T) {
+		st, err := LoadSecurityTxt(write(t, "# comment\nContact: mailto:security@example.com\nExpires: 2027-01-01T00:00:00Z\n"))
+		require.NoError(t, err)
+		assert.Empty(t, st.Validate(now))
+	})
+
+	t.Run("expired and missing contact", func(t *testing.T) {
+		st, err := LoadSecurityTxt(write(t, "Expires: 2025-01-01T00:00:00Z\n"))
+		require.NoError(t, err)
+		problems := st.Validate(now)
+		assert.Contains(t, problems, "Contact field is required")
+		assert.Contains(t, problems, "Expires 2025-01-01T00:00:00Z is in the past")
+	})
+
+	t.Run("prose and bad values", func(t *testing.T) {
+		st, err := LoadSecurityTxt(write(t, "Please email us\nContact: security@example.com\nExpires: next year\n"))
+		require.NoError(t, err)
+		problems := st.Validate(now)
+		assert.Len(t, problems, 3)
+		assert.Contains(t, problems[0], "line 1 is not a field")
+	})
+
+	t.Run("site security.txt is valid", func(t *testing.T) {
+		path, err := FindSecurityTxt(filepath.Join("..", SiteDir, "static"))
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join("..", SiteDir, "static", ".well-known", "security.txt"), path)
+
+		st, err := LoadSecurityTxt(path)
+		require.NoError(t, err)
+		assert.Empty(t, st.Validate(time.Now()))
+	})
+}
@@ -0,0 +1,183 @@
+package support
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// RootDir is the repository root, relative to the test directory
+const RootDir = ".."
+
+// SiteBuild produces a static build of the Hugo site for one environment.
+// Config layers follow the repo's `--config config/<env>/hugo.toml,hugo.toml`
+// convention: files listed first take precedence.
+type SiteBuild struct {
+	Environment  string
+	OutputDir    string
+	ConfigLayers []string     // extra layers, applied over the environment config
+	Flags        FeatureFlags // mirrored into site.Params.Flags when set
+	Args         []string     // extra hugo arguments
+}
+
+// NewSiteBuild creates a build of the given environment into outputDir
+func NewSiteBuild(environment, outputDir string) *SiteBuild {
+	return &SiteBuild{
+		Environment: environment,
+		OutputDir:   outputDir,
+	}
+}
+
+// EnvironmentConfigPath returns config/<env>/hugo.toml for an environment
+func EnvironmentConfigPath(environment string) string {
+	return filepath.Join(RootDir, "config", environment, "hugo.toml")
+}
+
+// SiteConfigPath returns the site's base hugo.toml
+func SiteConfigPath() string {
+	return filepath.Join(SiteDir, "hugo.toml")
+}
+
+// configArg builds the --config value, writing the flags layer into workDir if needed
+func (b *SiteBuild) configArg(workDir string) (string, error) {
+	var layers []string
+
+	if b.Flags != nil {
+		flagsPath := filepath.Join(workDir, "feature-flags.toml")
+		if err := b.Flags.WriteHugoConfig(flagsPath); err != nil {
+			return "", err
+		}
+		layers = append(layers, flagsPath)
+	}
+	layers = append(layers, b.ConfigLayers...)
+
+	envConfig := EnvironmentConfigPath(b.Environment)
+	if _, err := os.Stat(envConfig); err != nil {
+		return "", fmt.Errorf("no config for environment %q: %w", b.Environment, err)
+	}
+	layers = append(layers, envConfig, SiteConfigPath())
+
+	for i, layer := range layers {
+		abs, err := filepath.Abs(layer)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve config %s: %w", layer, err)
+		}
+		layers[i] = abs
+	}
+
+	return strings.Join(layers, ","), nil
+}
+
+// Run executes hugo and returns its combined output on failure
+func (b *SiteBuild) Run() error {
+	workDir, err := os.MkdirTemp("", "hugo-build-")
+	if err != nil {
+		return fmt.Errorf("failed to create build work dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	config, err := b.configArg(workDir)
+	if err != nil {
+		return err
+	}
+
+	source, err := filepath.Abs(SiteDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve site dir: %w", err)
+	}
+	destination, err := filepath.Abs(b.OutputDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve output dir: %w", err)
+	}
+
+	args := []string{
+		"--source", source,
+		"--destination", destination,
+		"--environment", b.Environment,
+		"--config", config,
+		"--cleanDestinationDir",
+		"--quiet",
+	}
+	args = append(args, b.Args...)
+
+	cmd := exec.Command("hugo", args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("hugo build for %s failed: %w\n%s", b.Environment, err, string(output))
+	}
+
+	return nil
+}
+
+// BuildAndServe builds an environment into a temp dir and serves it locally.
+// The build's baseURL is the server's URL so links resolve against it.
+// The caller owns the server; TestContext.ServeBuild cleans it up.
+func BuildAndServe(environment string, flags FeatureFlags) (*StaticServer, error) {
+	dir, err := os.MkdirTemp("", "site-"+environment+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create build dir: %w", err)
+	}
+
+	// The server must exist first so the build can use its URL as baseURL
+	server := NewStaticServer(dir)
+	build := NewSiteBuild(environment, dir)
+	build.Flags = flags
+	build.Args = []string{"--baseURL", server.URL() + "/"}
+
+	if err := build.Run(); err != nil {
+		server.Close()
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	return server, nil
+}
+
+// BuiltSite is a static build kept on disk for checks on the generated files
+type BuiltSite struct {
+	Dir         string
+	Environment string
+	BaseURL     string
+}
+
+// BuildSite builds an environment with its own baseURL into a temp dir for
+// inspecting the generated files. TestContext.UseBuild removes it afterwards.
+func BuildSite(environment string, flags FeatureFlags) (*BuiltSite, error) {
+	baseURL, err := EnvironmentBaseURL(environment)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "site-"+environment+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create build dir: %w", err)
+	}
+
+	build := NewSiteBuild(environment, dir)
+	build.Flags = flags
+	if err := build.Run(); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	return &BuiltSite{Dir: dir, Environment: environment, BaseURL: baseURL}, nil
+}
+
+// EnvironmentBaseURL returns the baseURL an environment builds with
+func EnvironmentBaseURL(environment string) (string, error) {
+	for _, path := range []string{EnvironmentConfigPath(environment), SiteConfigPath()} {
+		var config struct {
+			BaseURL string `toml:"baseURL"`
+		}
+		if _, err := toml.DecodeFile(path, &config); err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if config.BaseURL != "" {
+			return config.BaseURL, nil
+		}
+	}
+	return "", fmt.Errorf("no baseURL configured for environment %q", environment)
+}
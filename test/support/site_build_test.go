@@ -0,0 +1,54 @@
+package support
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSiteBuild_ConfigArg tests config layer ordering (first wins)
+func TestSiteBuild_ConfigArg(t *testing.T) {
+	// Paths are relative to the test directory
+	t.Chdir("..")
+
+	build := NewSiteBuild("staging", t.TempDir())
+	build.Flags = FeatureFlags{"books_section": {Enabled: true}}
+	build.ConfigLayers = []string{"extra.toml"}
+
+	workDir := t.TempDir()
+	config, err := build.configArg(workDir)
+	require.NoError(t, err)
+
+	layers := strings.Split(config, ",")
+	require.Len(t, layers, 4)
+	assert.Equal(t, filepath.Join(workDir, "feature-flags.toml"), layers[0])
+	assert.True(t, strings.HasSuffix(layers[1], "extra.toml"))
+	assert.True(t, strings.HasSuffix(layers[2], filepath.Join("config", "staging", "hugo.toml")))
+	assert.True(t, strings.HasSuffix(layers[3], filepath.Join("packages", "site", "hugo.toml")))
+	for _, layer := range layers {
+		assert.True(t, filepath.IsAbs(layer), layer)
+	}
+}
+
+// TestSiteBuild_UnknownEnvironment tests that a missing environment config fails early
+func TestSiteBuild_UnknownEnvironment(t *testing.T) {
+	build := NewSiteBuild("qa", t.TempDir())
+	_, err := build.configArg(t.TempDir())
+	assert.ErrorContains(t, err, `no config for environment "qa"`)
+}
+
+// TestEnvironmentBaseURL tests the environment config wins over the site config
+func TestEnvironmentBaseURL(t *testing.T) {
+	t.Chdir("..")
+
+	baseURL, err := EnvironmentBaseURL("staging")
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.pwarnock.github.io", baseURL)
+
+	baseURL, err = EnvironmentBaseURL("production")
+	require.NoError(t, err)
+	assert.Equal(t, "https://peterwarnock.com", baseURL)
+}
@@ -0,0 +1,132 @@
+package support
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SitemapPath is where Hugo writes the sitemap, relative to the build directory
+const SitemapPath = "sitemap.xml"
+
+// sitemapDocument covers both a <urlset> and a multilingual <sitemapindex>
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// LoadSitemapURLs returns every <loc> in a build's sitemap, following a
+// sitemap index into the sitemaps it lists
+func LoadSitemapURLs(site *BuiltSite) ([]string, error) {
+	return loadSitemap(site, SitemapPath, 0)
+}
+
+// loadSitemap parses one sitemap file; depth guards against index loops
+func loadSitemap(site *BuiltSite, rel string, depth int) ([]string, error) {
+	if depth > 2 {
+		return nil, fmt.Errorf("sitemap index nested too deeply at %s", rel)
+	}
+
+	data, err := os.ReadFile(filepath.Join(site.Dir, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+
+	var doc sitemapDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s is not valid XML: %w", rel, err)
+	}
+
+	switch doc.XMLName.Local {
+	case "urlset":
+		urls := make([]string, 0, len(doc.URLs))
+		for _, u := range doc.URLs {
+			urls = append(urls, strings.TrimSpace(u.Loc))
+		}
+		return urls, nil
+	case "sitemapindex":
+		var urls []string
+		for _, s := range doc.Sitemaps {
+			u, err := url.Parse(strings.TrimSpace(s.Loc))
+			if err != nil {
+				return nil, fmt.Errorf("invalid sitemap %q in %s: %w", s.Loc, rel, err)
+			}
+			nested, err := loadSitemap(site, strings.TrimPrefix(u.Path, "/"), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			urls = append(urls, nested...)
+		}
+		return urls, nil
+	default:
+		return nil, fmt.Errorf("%s has root <%s>, expected <urlset> or <sitemapindex>", rel, doc.XMLName.Local)
+	}
+}
+
+// CheckSitemapURLs returns the sitemap entries that are relative, on another
+// host than baseURL, or listed more than once
+func CheckSitemapURLs(urls []string, baseURL string) []string {
+	siteHost := hostOf(baseURL)
+	seen := make(map[string]bool, len(urls))
+
+	var problems []string
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("%s: invalid URL: %v", raw, err))
+		case !u.IsAbs() || u.Host == "":
+			problems = append(problems, raw+": not an absolute URL")
+		case !strings.EqualFold(u.Hostname(), siteHost):
+			problems = append(problems, fmt.Sprintf("%s: host is not %s", raw, siteHost))
+		}
+
+		if seen[raw] {
+			problems = append(problems, raw+": listed more than once")
+		}
+		seen[raw] = true
+	}
+	return problems
+}
+
+// CheckURLStatuses requests the path of each URL from serverURL and returns
+// those that do not end in 200 OK. Redirects are followed.
+func CheckURLStatuses(client *http.Client, serverURL string, urls []string) []string {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	var problems []string
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil {
+			continue
+		}
+		target := strings.TrimSuffix(serverURL, "/") + path.Clean("/"+u.Path)
+		if strings.HasSuffix(u.Path, "/") && u.Path != "/" {
+			target += "/"
+		}
+
+		resp, err := client.Get(target)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", raw, err))
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			problems = append(problems, fmt.Sprintf("%s: HTTP %d", raw, resp.StatusCode))
+		}
+	}
+	return problems
+}
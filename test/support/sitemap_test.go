@@ -0,0 +1,70 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeBuildFile writes a file into a fake build directory
+func writeBuildFile(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	p := filepath.Join(dir, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+}
+
+// TestLoadSitemapURLs_Index tests a sitemap index is followed into its sitemaps
+func TestLoadSitemapURLs_Index(t *testing.T) {
+	site := &BuiltSite{Dir: t.TempDir(), BaseURL: "https://example.com/"}
+	writeBuildFile(t, site.Dir, "sitemap.xml", `<?xml version="1.0" encoding="utf-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/en/sitemap.xml</loc></sitemap>
+</sitemapindex>`)
+	writeBuildFile(t, site.Dir, "en/sitemap.xml", `<?xml version="1.0" encoding="utf-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/</loc></url>
+  <url><loc> https://example.com/blog/ </loc></url>
+</urlset>`)
+
+	urls, err := LoadSitemapURLs(site)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/", "https://example.com/blog/"}, urls)
+}
+
+// TestCheckSitemapURLs tests relative, foreign and duplicate entries are reported
+func TestCheckSitemapURLs(t *testing.T) {
+	problems := CheckSitemapURLs([]string{
+		"https://example.com/",
+		"/blog/",
+		"https://staging.example.com/tools/",
+		"https://example.com/",
+	}, "https://example.com/")
+
+	assert.Equal(t, []string{
+		"/blog/: not an absolute URL",
+		"https://staging.example.com/tools/: host is not example.com",
+		"https://example.com/: listed more than once",
+	}, problems)
+}
+
+// TestCheckURLStatuses tests sitemap paths are requested from the local server
+func TestCheckURLStatuses(t *testing.T) {
+	dir := t.TempDir()
+	writeBuildFile(t, dir, "index.html", "home")
+	writeBuildFile(t, dir, "blog/index.html", "blog")
+	writeBuildFile(t, dir, "404.html", "not found")
+
+	server := NewStaticServer(dir)
+	defer server.Close()
+
+	problems := CheckURLStatuses(nil, server.URL(), []string{
+		"https://example.com/",
+		"https://example.com/blog/",
+		"https://example.com/missing/",
+	})
+	assert.Equal(t, []string{"https://example.com/missing/: HTTP 404"}, problems)
+}
@@ -0,0 +1,371 @@
+package support
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LayoutsDir is the site's Hugo templates
+const LayoutsDir = SiteDir + "/layouts"
+
+// maxSourceCandidates caps the templates suggested per selector
+const maxSourceCandidates = 3
+
+// SourceLocation is a file that probably renders part of a page
+type SourceLocation struct {
+	Path   string `json:"path"`   // relative to the repository root
+	Reason string `json:"reason"` // e.g. "content of /tools/bun/" or "matches .card-title"
+}
+
+// String formats the location for failure messages
+func (l SourceLocation) String() string {
+	return fmt.Sprintf("%s (%s)", l.Path, l.Reason)
+}
+
+// templateMarkers are the static classes and ids a template writes
+type templateMarkers struct {
+	path    string // relative to the repository root
+	name    string // file name without extension, e.g. card-tools
+	classes map[string]bool
+	ids     map[string]bool
+}
+
+// SourceMap resolves rendered pages back to content files and selectors to
+// the templates that probably wrote the matching elements
+type SourceMap struct {
+	pages     map[string]string // URL path -> content file
+	templates []templateMarkers
+	frequency map[string]int // class or id -> number of templates writing it
+}
+
+// LoadSourceMap indexes the content and layouts. Content is mapped with the
+// same rules as ContentItem.URLPath; when hugo is installed its own
+// permalinks from `hugo list all` replace them.
+func LoadSourceMap(environment string) (*SourceMap, error) {
+	m := &SourceMap{pages: make(map[string]string), frequency: make(map[string]int)}
+
+	items, err := LoadContent(ContentDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		m.pages[item.URLPath()] = path.Join(repoRelative(ContentDir), item.Path)
+	}
+
+	if _, err := exec.LookPath("hugo"); err == nil {
+		if err := m.addHugoList(environment); err != nil {
+			fmt.Printf("Warning: %v; mapping URLs from frontmatter\n", err)
+		}
+	}
+
+	if err := m.indexTemplates(LayoutsDir); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// addHugoList maps URLs with the permalinks hugo reports for an environment
+func (m *SourceMap) addHugoList(environment string) error {
+	build := NewSiteBuild(environment, "")
+	config, err := build.configArg(os.TempDir())
+	if err != nil {
+		return err
+	}
+	source, err := filepath.Abs(SiteDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve site dir: %w", err)
+	}
+
+	output, err := exec.Command("hugo", "list", "all", "--source", source, "--environment", environment, "--config", config).Output()
+	if err != nil {
+		return fmt.Errorf("hugo list all failed: %w", err)
+	}
+	return m.AddHugoList(strings.NewReader(string(output)))
+}
+
+// AddHugoList reads the CSV printed by `hugo list all` and maps each
+// permalink's path to its content file
+func (m *SourceMap) AddHugoList(r io.Reader) error {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to parse hugo list output: %w", err)
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	pathCol, permalinkCol := -1, -1
+	for i, column := range records[0] {
+		switch column {
+		case "path":
+			pathCol = i
+		case "permalink":
+			permalinkCol = i
+		}
+	}
+	if pathCol < 0 || permalinkCol < 0 {
+		return fmt.Errorf("hugo list output has no path and permalink columns")
+	}
+
+	for _, record := range records[1:] {
+		u, err := url.Parse(record[permalinkCol])
+		if err != nil {
+			continue
+		}
+		// hugo lists paths relative to the site source
+		m.pages[ensureTrailingSlash(u.Path)] = path.Join(repoRelative(SiteDir), filepath.ToSlash(record[pathCol]))
+	}
+	return nil
+}
+
+// classAttrPattern and idAttrPattern find static class and id attributes
+var (
+	classAttrPattern = regexp.MustCompile(`\bclass="([^"]*)"`)
+	idAttrPattern    = regexp.MustCompile(`\bid="([^"]*)"`)
+	templateActions  = regexp.MustCompile(`\{\{.*?\}\}`)
+)
+
+// indexTemplates records the static classes and ids of every HTML template
+func (m *SourceMap) indexTemplates(dir string) error {
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(p) != ".html" {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		m.addTemplate(repoRelative(p), string(data))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to index templates in %s: %w", dir, err)
+	}
+	return nil
+}
+
+// addTemplate indexes one template's markup. Template actions are dropped,
+// so only classes written literally count.
+func (m *SourceMap) addTemplate(p, markup string) {
+	t := templateMarkers{
+		path:    p,
+		name:    strings.TrimSuffix(path.Base(p), path.Ext(p)),
+		classes: make(map[string]bool),
+		ids:     make(map[string]bool),
+	}
+	for _, match := range classAttrPattern.FindAllStringSubmatch(markup, -1) {
+		for _, class := range strings.Fields(templateActions.ReplaceAllString(match[1], " ")) {
+			t.classes[class] = true
+		}
+	}
+	for _, match := range idAttrPattern.FindAllStringSubmatch(markup, -1) {
+		if id := match[1]; id != "" && !templateActions.MatchString(id) {
+			t.ids[id] = true
+		}
+	}
+
+	for class := range t.classes {
+		m.frequency["."+class]++
+	}
+	for id := range t.ids {
+		m.frequency["#"+id]++
+	}
+	m.templates = append(m.templates, t)
+}
+
+// ContentFor returns the content file rendered at a page URL
+func (m *SourceMap) ContentFor(pageURL string) (SourceLocation, bool) {
+	p := pageURL
+	if u, err := url.Parse(pageURL); err == nil {
+		p = u.Path
+	}
+	p = ensureTrailingSlash(p)
+	file, ok := m.pages[p]
+	if !ok {
+		return SourceLocation{}, false
+	}
+	return SourceLocation{Path: file, Reason: "content of " + p}, true
+}
+
+// selectorTokenPattern finds the class and id tokens of a CSS selector
+var selectorTokenPattern = regexp.MustCompile(`[.#](?:\\.|[\w-])+`)
+
+// TemplatesFor ranks the templates that write the classes and ids of a
+// selector. Rare markers weigh more than utility classes used everywhere,
+// and a class named after the template file counts double.
+func (m *SourceMap) TemplatesFor(selector string) []SourceLocation {
+	var tokens []string
+	for _, token := range selectorTokenPattern.FindAllString(selector, -1) {
+		tokens = append(tokens, strings.ReplaceAll(token, `\`, ""))
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	type candidate struct {
+		location SourceLocation
+		score    float64
+	}
+	var candidates []candidate
+	for _, t := range m.templates {
+		score := 0.0
+		var matched []string
+		for _, token := range tokens {
+			var found bool
+			if token[0] == '#' {
+				found = t.ids[token[1:]]
+			} else {
+				found = t.classes[token[1:]]
+			}
+			if !found {
+				continue
+			}
+			weight := math.Log(float64(len(m.templates)+1) / float64(m.frequency[token]))
+			if token[1:] == t.name {
+				weight *= 2
+			}
+			score += weight
+			matched = append(matched, token)
+		}
+		if score > 0 {
+			candidates = append(candidates, candidate{
+				location: SourceLocation{Path: t.path, Reason: "matches " + strings.Join(matched, "")},
+				score:    score,
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].location.Path < candidates[j].location.Path
+	})
+	if len(candidates) > maxSourceCandidates {
+		candidates = candidates[:maxSourceCandidates]
+	}
+
+	locations := make([]SourceLocation, len(candidates))
+	for i, c := range candidates {
+		locations[i] = c.location
+	}
+	return locations
+}
+
+// Locate returns the likely sources of a page's failing nodes: the page's
+// content file followed by the templates matching each selector, without
+// duplicates
+func (m *SourceMap) Locate(pageURL string, selectors ...string) []SourceLocation {
+	var locations []SourceLocation
+	seen := make(map[string]bool)
+	add := func(l SourceLocation) {
+		if !seen[l.Path] {
+			seen[l.Path] = true
+			locations = append(locations, l)
+		}
+	}
+
+	if content, ok := m.ContentFor(pageURL); ok {
+		add(content)
+	}
+	for _, selector := range selectors {
+		for _, l := range m.TemplatesFor(selector) {
+			add(l)
+		}
+	}
+	return locations
+}
+
+// LocateGroup returns the likely sources of a grouped violation: the
+// templates matching its selectors, then the content files of up to
+// maxSourceCandidates affected pages
+func (m *SourceMap) LocateGroup(g A11yGroup) []SourceLocation {
+	var locations []SourceLocation
+	for _, selector := range g.selectors() {
+		for _, l := range m.TemplatesFor(selector) {
+			if !containsSource(locations, l) {
+				locations = append(locations, l)
+			}
+		}
+	}
+	candidates := 0
+	for _, page := range g.Pages {
+		if candidates == maxSourceCandidates {
+			break
+		}
+		if content, ok := m.ContentFor(page.URL); ok && !containsSource(locations, content) {
+			locations = append(locations, content)
+			candidates++
+		}
+	}
+	return locations
+}
+
+// SummarizeViolations summarizes violations like the package-level
+// SummarizeViolations, adding the likely sources of each violation's nodes
+func (m *SourceMap) SummarizeViolations(pageURL string, violations []AxeViolation) string {
+	var b strings.Builder
+	for _, v := range violations {
+		fmt.Fprintf(&b, "\n  - %s (%s): %s [%d nodes]", v.ID, v.Impact, v.Help, len(v.Nodes))
+		selectors := make([]string, 0, len(v.Nodes))
+		for _, node := range v.Nodes {
+			selectors = append(selectors, strings.Join(node.Target, " "))
+		}
+		for _, l := range m.Locate(pageURL, selectors...) {
+			fmt.Fprintf(&b, "\n      source: %s", l)
+		}
+	}
+	return b.String()
+}
+
+// repoRelative turns a path under RootDir into a repository-relative one
+func repoRelative(p string) string {
+	rel, err := filepath.Rel(RootDir, p)
+	if err != nil {
+		return filepath.ToSlash(p)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// developmentSources caches the development source map; the content and
+// layouts do not change while the suite runs
+var developmentSources struct {
+	once sync.Once
+	m    *SourceMap
+	err  error
+}
+
+// DevelopmentSourceMap returns the development source map, indexing the site
+// on first use only
+func DevelopmentSourceMap() (*SourceMap, error) {
+	developmentSources.once.Do(func() {
+		developmentSources.m, developmentSources.err = LoadSourceMap("development")
+	})
+	return developmentSources.m, developmentSources.err
+}
+
+// SummarizeViolationsWithSources is SummarizeViolations with the likely
+// sources of each violation, falling back to the plain summary when the site
+// cannot be indexed
+func SummarizeViolationsWithSources(pageURL string, violations []AxeViolation) string {
+	m, err := DevelopmentSourceMap()
+	if err != nil {
+		return SummarizeViolations(violations)
+	}
+	return m.SummarizeViolations(pageURL, violations)
+}
@@ -0,0 +1,126 @@
+package support
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSourceMap_TemplatesFor tests rare markers outrank shared utility classes
+func TestSourceMap_TemplatesFor(t *testing.T) {
+	m := &SourceMap{pages: map[string]string{}, frequency: make(map[string]int)}
+	m.addTemplate("packages/site/layouts/partials/components/card-tools.html",
+		`<div class="card-tools card {{ $size }}"><h2 class="card-title flex">{{ .Title }}</h2><img class="tool-logo" src="x"></div>`)
+	m.addTemplate("packages/site/layouts/partials/components/card-blog.html",
+		`<div class="card flex"><h2 class="card-title">{{ .Title }}</h2></div>`)
+	m.addTemplate("packages/site/layouts/partials/header.html",
+		`<header id="site-header" class="navbar flex"><a id="{{ .ID }}" class="btn">x</a></header>`)
+
+	locations := m.TemplatesFor("div.card > h2.card-title > img.tool-logo")
+	require.NotEmpty(t, locations)
+	assert.Equal(t, "packages/site/layouts/partials/components/card-tools.html", locations[0].Path)
+	assert.Equal(t, "matches .card.card-title.tool-logo", locations[0].Reason)
+
+	locations = m.TemplatesFor("#site-header > .btn")
+	require.Len(t, locations, 1)
+	assert.Equal(t, "packages/site/layouts/partials/header.html", locations[0].Path)
+
+	assert.Empty(t, m.TemplatesFor("main > p"), "no classes or ids")
+	assert.Empty(t, m.TemplatesFor(".unknown"))
+}
+
+// TestSourceMap_TemplatesFor_EscapedClass tests Tailwind variant classes in selectors
+func TestSourceMap_TemplatesFor_EscapedClass(t *testing.T) {
+	m := &SourceMap{pages: map[string]string{}, frequency: make(map[string]int)}
+	m.addTemplate("packages/site/layouts/partials/footer.html", `<a class="hover:underline">x</a>`)
+
+	locations := m.TemplatesFor(`a.hover\:underline`)
+	require.Len(t, locations, 1)
+	assert.Equal(t, "packages/site/layouts/partials/footer.html", locations[0].Path)
+}
+
+// TestSourceMap_Locate tests the content file comes first, then templates
+func TestSourceMap_Locate(t *testing.T) {
+	m := &SourceMap{pages: map[string]string{"/tools/bun/": "packages/site/content/tools/bun/index.md"}, frequency: make(map[string]int)}
+	m.addTemplate("packages/site/layouts/partials/components/card-tools.html",
+		`<div class="card-tools card"><img class="tool-logo" src="x"></div>`)
+
+	locations := m.Locate("http://localhost:1313/tools/bun/", ".tool-logo", ".card-tools")
+	require.Len(t, locations, 2, "the same template is listed once")
+	assert.Equal(t, "packages/site/content/tools/bun/index.md (content of /tools/bun/)", locations[0].String())
+	assert.Equal(t, "packages/site/layouts/partials/components/card-tools.html", locations[1].Path)
+
+	summary := m.SummarizeViolations("http://localhost:1313/tools/bun/", []AxeViolation{
+		{ID: "image-alt", Impact: "critical", Help: "Images must have alternate text", Nodes: []AxeNode{{Target: []string{"img.tool-logo"}}}},
+	})
+	assert.Contains(t, summary, "image-alt (critical)")
+	assert.Contains(t, summary, "source: packages/site/layouts/partials/components/card-tools.html (matches .tool-logo)")
+}
+
+// TestSourceMap_AddHugoList tests hugo permalinks replace frontmatter URLs
+func TestSourceMap_AddHugoList(t *testing.T) {
+	m := &SourceMap{pages: map[string]string{"/tools/bun/": "packages/site/content/tools/bun/index.md"}, frequency: make(map[string]int)}
+	list := "path,slug,title,date,expiryDate,publishDate,draft,permalink,kind,section\n" +
+		"content/tools/bun/index.md,,Bun,2025-01-01T00:00:00Z,,,false,http://localhost:1313/tools/bun-runtime/,page,tools\n"
+
+	require.NoError(t, m.AddHugoList(strings.NewReader(list)))
+	location, ok := m.ContentFor("/tools/bun-runtime/")
+	require.True(t, ok)
+	assert.Equal(t, "packages/site/content/tools/bun/index.md", location.Path)
+
+	assert.Error(t, m.AddHugoList(strings.NewReader("title,date\nx,y\n")))
+}
+
+// TestLoadSourceMap tests the site's content and layouts are indexed
+func TestLoadSourceMap(t *testing.T) {
+	t.Chdir("..")
+
+	m, err := LoadSourceMap("development")
+	require.NoError(t, err)
+
+	location, ok := m.ContentFor("/tools/")
+	require.True(t, ok)
+	assert.Equal(t, "packages/site/content/tools/_index.md", location.Path)
+
+	location, ok = m.ContentFor("http://localhost:1313/")
+	require.True(t, ok)
+	assert.Equal(t, "packages/site/content/_index.md", location.Path)
+
+	locations := m.TemplatesFor("nav.navbar")
+	require.NotEmpty(t, locations)
+	assert.Contains(t, pathsOf(locations), "packages/site/layouts/partials/components/navigation.html")
+}
+
+// TestDevelopmentSourceMap tests the site is indexed once per process
+func TestDevelopmentSourceMap(t *testing.T) {
+	first, firstErr := DevelopmentSourceMap()
+	second, secondErr := DevelopmentSourceMap()
+	assert.Same(t, first, second)
+	assert.Equal(t, firstErr, secondErr)
+}
+
+// TestA11yGroup_Description_Sources tests likely sources are listed in issues
+func TestA11yGroup_Description_Sources(t *testing.T) {
+	group := IssueGroups(GroupOccurrences(OccurrencesFromViolations("/tools/bun/", []AxeViolation{cardImageViolation("img.tool-logo")})))[0]
+	m := &SourceMap{pages: map[string]string{"/tools/bun/": "packages/site/content/tools/bun/index.md"}, frequency: make(map[string]int)}
+	m.addTemplate("packages/site/layouts/partials/components/card-tools.html",
+		`<div class="card-tools card"><img class="tool-logo" src="x"></div>`)
+	group.Sources = m.LocateGroup(group)
+
+	description := group.Description()
+	assert.Contains(t, description, "### Likely sources")
+	assert.Contains(t, description, "- `packages/site/layouts/partials/components/card-tools.html` (matches .tool-logo)")
+	assert.Contains(t, description, "- `packages/site/content/tools/bun/index.md` (content of /tools/bun/)")
+	assert.Len(t, PagesInDescription(description), 1, "source lines are not page rows")
+}
+
+// pathsOf returns the paths of locations, for assertions
+func pathsOf(locations []SourceLocation) []string {
+	paths := make([]string, len(locations))
+	for i, l := range locations {
+		paths[i] = l.Path
+	}
+	return paths
+}
@@ -0,0 +1,304 @@
+package support
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// CDNDataPath is the site's CDN and analytics configuration
+const CDNDataPath = SiteDir + "/data/cdn.toml"
+
+// CDNFixturesDir holds local copies of CDN resources, stored as <host>/<path>
+const CDNFixturesDir = "fixtures/cdn"
+
+// CDNEntry is one [[cdns]] entry in data/cdn.toml
+type CDNEntry struct {
+	Name        string `toml:"name"`
+	URL         string `toml:"url"`
+	Type        string `toml:"type"`
+	Description string `toml:"description"`
+	Integrity   string `toml:"integrity"`
+}
+
+// EmbedEntry is one [[embeds]] entry in data/cdn.toml: a third-party widget
+// host whose scripts are exempt from SRI
+type EmbedEntry struct {
+	Host   string `toml:"host"`
+	Reason string `toml:"reason"`
+}
+
+// CDNConfig mirrors data/cdn.toml
+type CDNConfig struct {
+	CDNs      []CDNEntry   `toml:"cdns"`
+	Embeds    []EmbedEntry `toml:"embeds"`
+	Analytics struct {
+		Provider    string `toml:"provider"`
+		ContainerID string `toml:"container_id"`
+		BaseURL     string `toml:"base_url"`
+	} `toml:"analytics"`
+}
+
+// LoadCDNConfig reads data/cdn.toml
+func LoadCDNConfig(path string) (*CDNConfig, error) {
+	var config CDNConfig
+	if _, err := toml.DecodeFile(path, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse CDN config %s: %w", path, err)
+	}
+	for _, embed := range config.Embeds {
+		if embed.Host == "" || strings.TrimSpace(embed.Reason) == "" {
+			return nil, fmt.Errorf("CDN config %s: embed %q needs a host and a reason", path, embed.Host)
+		}
+	}
+	return &config, nil
+}
+
+// EmbedFor returns the embed entry covering a URL's host
+func (c *CDNConfig) EmbedFor(rawURL string) (EmbedEntry, bool) {
+	for _, embed := range c.Embeds {
+		if hostMatches(hostOf(rawURL), embed.Host) {
+			return embed, true
+		}
+	}
+	return EmbedEntry{}, false
+}
+
+// AllowedHosts returns the hosts external scripts and stylesheets may load from
+func (c *CDNConfig) AllowedHosts() []string {
+	var hosts []string
+	for _, cdn := range c.CDNs {
+		if host := hostOf(cdn.URL); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// ExternalResource is a <script> or <link> loading from another origin
+type ExternalResource struct {
+	Tag         string
+	URL         string
+	Integrity   string
+	CrossOrigin string
+	HasCORS     bool
+	Async       bool // async or defer, so the resource cannot block rendering
+	Line        int
+}
+
+// String names the resource for failure messages
+func (r ExternalResource) String() string {
+	return fmt.Sprintf("line %d: <%s> %s", r.Line, r.Tag, r.URL)
+}
+
+// sriLinkRels are the <link> relations that fetch content SRI can protect
+var sriLinkRels = map[string]bool{"stylesheet": true, "preload": true, "modulepreload": true}
+
+// FindExternalResources returns scripts and stylesheets loaded from hosts
+// other than siteURL's. Relative references are always local.
+func FindExternalResources(document, siteURL string) []ExternalResource {
+	siteHost := hostOf(siteURL)
+
+	var resources []ExternalResource
+	for _, tag := range ParseTags(document) {
+		var ref string
+		switch tag.Name {
+		case "script":
+			ref = tag.Attr("src")
+		case "link":
+			if !hasAnyRel(tag.Attr("rel"), sriLinkRels) {
+				continue
+			}
+			ref = tag.Attr("href")
+		default:
+			continue
+		}
+
+		resolved, ok := externalURL(ref, siteHost)
+		if !ok {
+			continue
+		}
+		resources = append(resources, ExternalResource{
+			Tag:         tag.Name,
+			URL:         resolved,
+			Integrity:   tag.Attr("integrity"),
+			CrossOrigin: tag.Attr("crossorigin"),
+			HasCORS:     tag.HasAttr("crossorigin"),
+			Async:       tag.HasAttr("async") || tag.HasAttr("defer"),
+			Line:        tag.Line,
+		})
+	}
+	return resources
+}
+
+// hasAnyRel reports whether a space-separated rel value contains any of rels
+func hasAnyRel(rel string, rels map[string]bool) bool {
+	for _, r := range strings.Fields(strings.ToLower(rel)) {
+		if rels[r] {
+			return true
+		}
+	}
+	return false
+}
+
+// externalURL resolves protocol-relative references and reports whether
+// ref points at a host other than siteHost
+func externalURL(ref, siteHost string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(ref, "//") {
+		ref = "https:" + ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" || host == siteHost {
+		return "", false
+	}
+	return u.String(), true
+}
+
+// CheckExternalResource returns the SRI and allowlist problems with a resource
+func CheckExternalResource(res ExternalResource, allowedHosts []string) []string {
+	var problems []string
+
+	if res.Integrity == "" {
+		problems = append(problems, "missing integrity attribute")
+	} else if _, err := ParseIntegrity(res.Integrity); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if !res.HasCORS {
+		problems = append(problems, `missing crossorigin attribute (use crossorigin="anonymous")`)
+	}
+
+	allowed := false
+	for _, host := range allowedHosts {
+		if hostMatches(hostOf(res.URL), host) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		problems = append(problems, fmt.Sprintf("host %s is not listed in %s", hostOf(res.URL), filepath.Base(CDNDataPath)))
+	}
+
+	return problems
+}
+
+// CheckEmbedResource returns the problems with a resource from an embed host:
+// only scripts may load from one, and they must not block rendering
+func CheckEmbedResource(res ExternalResource) []string {
+	var problems []string
+	if res.Tag != "script" {
+		problems = append(problems, fmt.Sprintf("embed hosts may only serve scripts, not <%s>", res.Tag))
+	} else if !res.Async {
+		problems = append(problems, "embed script must load with async or defer")
+	}
+	return problems
+}
+
+// IntegrityHash is one "<alg>-<base64 digest>" entry of an integrity attribute
+type IntegrityHash struct {
+	Algorithm string
+	Digest    string
+}
+
+// integrityAlgorithms ranks supported algorithms; browsers only use the strongest present
+var integrityAlgorithms = map[string]int{"sha256": 1, "sha384": 2, "sha512": 3}
+
+// integrityPattern matches one hash expression, ignoring trailing ?options
+var integrityPattern = regexp.MustCompile(`^(sha256|sha384|sha512)-([A-Za-z0-9+/]+={0,2})(\?.*)?$`)
+
+// ParseIntegrity parses a space-separated integrity attribute
+func ParseIntegrity(integrity string) ([]IntegrityHash, error) {
+	var hashes []IntegrityHash
+	for _, expr := range strings.Fields(integrity) {
+		m := integrityPattern.FindStringSubmatch(expr)
+		if m == nil {
+			return nil, fmt.Errorf("invalid integrity value %q, expected sha256|sha384|sha512-<base64>", expr)
+		}
+		hashes = append(hashes, IntegrityHash{Algorithm: m[1], Digest: m[2]})
+	}
+	if len(hashes) == 0 {
+		return nil, fmt.Errorf("empty integrity attribute")
+	}
+	return hashes, nil
+}
+
+// ComputeIntegrity returns the integrity value for content with the given algorithm
+func ComputeIntegrity(algorithm string, content []byte) (string, error) {
+	var h hash.Hash
+	switch algorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha384":
+		h = sha512.New384()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return "", fmt.Errorf("unsupported integrity algorithm %q", algorithm)
+	}
+	h.Write(content)
+	return algorithm + "-" + base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyIntegrity checks content against an integrity attribute the way a
+// browser does: any hash using the strongest listed algorithm must match
+func VerifyIntegrity(integrity string, content []byte) error {
+	hashes, err := ParseIntegrity(integrity)
+	if err != nil {
+		return err
+	}
+
+	strongest := ""
+	for _, h := range hashes {
+		if integrityAlgorithms[h.Algorithm] > integrityAlgorithms[strongest] {
+			strongest = h.Algorithm
+		}
+	}
+
+	actual, err := ComputeIntegrity(strongest, content)
+	if err != nil {
+		return err
+	}
+	for _, h := range hashes {
+		if h.Algorithm == strongest && h.Algorithm+"-"+h.Digest == actual {
+			return nil
+		}
+	}
+	return fmt.Errorf("integrity mismatch: content hashes to %s", actual)
+}
+
+// Fetcher retrieves the content of an external resource
+type Fetcher func(rawURL string) ([]byte, error)
+
+// FixtureFetcher serves resources from dir/<host>/<path>, so integrity can be
+// checked offline against pinned copies of CDN files. URLs resolving outside
+// dir are rejected.
+func FixtureFetcher(dir string) Fetcher {
+	return func(rawURL string) ([]byte, error) {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL %s: %w", rawURL, err)
+		}
+		rel := filepath.Join(u.Hostname(), filepath.FromSlash(strings.TrimPrefix(u.Path, "/")))
+		if u.Hostname() == "" || containsString(strings.Split(u.Path, "/"), "..") || !filepath.IsLocal(rel) {
+			return nil, fmt.Errorf("refusing fixture path outside %s for %s", dir, rawURL)
+		}
+		path := filepath.Join(dir, rel)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("no fixture for %s (expected %s)", rawURL, path)
+		}
+		return content, nil
+	}
+}
@@ -0,0 +1,157 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadCDNConfig tests the site's cdn.toml allowlist
+func TestLoadCDNConfig(t *testing.T) {
+	config, err := LoadCDNConfig(filepath.Join("..", CDNDataPath))
+	require.NoError(t, err)
+
+	assert.Contains(t, config.AllowedHosts(), "cdn.tailwindcss.com")
+	assert.Equal(t, "https://www.googletagmanager.com", config.Analytics.BaseURL)
+
+	// The x and substack shortcodes load widget scripts that cannot be pinned
+	for _, script := range []string{"https://platform.twitter.com/widgets.js", "https://substack.com/embedjs/embed.js"} {
+		embed, ok := config.EmbedFor(script)
+		assert.True(t, ok, script)
+		assert.NotEmpty(t, embed.Reason, script)
+	}
+	_, ok := config.EmbedFor("https://cdn.tailwindcss.com/3.4.1")
+	assert.False(t, ok)
+}
+
+// TestLoadCDNConfig_EmbedNeedsReason tests embeds without a reason are rejected
+func TestLoadCDNConfig_EmbedNeedsReason(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cdn.toml")
+	require.NoError(t, os.WriteFile(path, []byte("[[embeds]]\nhost = \"widgets.example.com\"\n"), 0o644))
+
+	_, err := LoadCDNConfig(path)
+	assert.ErrorContains(t, err, "needs a host and a reason")
+}
+
+// TestFindExternalResources tests which references count as external
+func TestFindExternalResources(t *testing.T) {
+	doc := `<head>
+<script defer src="/js/alpinejs.min.abc.js" integrity="sha384-x" crossorigin="anonymous"></script>
+<script src="https://peterwarnock.com/js/app.js"></script>
+<script src="https://cdn.example.com/lib.js"></script>
+<link rel="stylesheet" href="//cdn.example.com/lib.css" integrity="sha384-y" crossorigin>
+<link rel="canonical" href="https://other.example.com/">
+<link rel="preconnect" href="https://fonts.example.com">
+<script type="application/ld+json">{}</script>
+</head>`
+
+	resources := FindExternalResources(doc, "https://peterwarnock.com")
+	require.Len(t, resources, 2)
+
+	assert.Equal(t, "script", resources[0].Tag)
+	assert.Equal(t, "https://cdn.example.com/lib.js", resources[0].URL)
+	assert.False(t, resources[0].HasCORS)
+
+	assert.Equal(t, "link", resources[1].Tag)
+	assert.Equal(t, "https://cdn.example.com/lib.css", resources[1].URL)
+	assert.True(t, resources[1].HasCORS)
+	assert.Equal(t, "sha384-y", resources[1].Integrity)
+	assert.False(t, resources[0].Async)
+}
+
+// TestCheckEmbedResource tests embed scripts must load without blocking rendering
+func TestCheckEmbedResource(t *testing.T) {
+	script := ExternalResource{Tag: "script", URL: "https://platform.twitter.com/widgets.js", Async: true}
+	assert.Empty(t, CheckEmbedResource(script))
+
+	blocking := script
+	blocking.Async = false
+	assert.Equal(t, []string{"embed script must load with async or defer"}, CheckEmbedResource(blocking))
+
+	stylesheet := ExternalResource{Tag: "link", URL: "https://substack.com/embed.css"}
+	assert.Contains(t, CheckEmbedResource(stylesheet)[0], "may only serve scripts")
+}
+
+// TestCheckExternalResource tests SRI attribute and allowlist problems
+func TestCheckExternalResource(t *testing.T) {
+	allowed := []string{"cdn.tailwindcss.com"}
+
+	ok := ExternalResource{
+		URL:       "https://cdn.tailwindcss.com/3.4.1",
+		Integrity: "sha384-oqVuAfXRKap7fdgcCY5uykM6+R9GqQ8K/uxy9rx7HNQlGYl1kPzQho1wx4JwY8wC",
+		HasCORS:   true,
+	}
+	assert.Empty(t, CheckExternalResource(ok, allowed))
+
+	bad := ExternalResource{URL: "https://evil.example.com/x.js"}
+	problems := CheckExternalResource(bad, allowed)
+	require.Len(t, problems, 3)
+	assert.Equal(t, "missing integrity attribute", problems[0])
+	assert.Contains(t, problems[1], "crossorigin")
+	assert.Contains(t, problems[2], "evil.example.com is not listed in cdn.toml")
+
+	malformed := ok
+	malformed.Integrity = "md5-abc"
+	assert.Contains(t, CheckExternalResource(malformed, allowed)[0], "invalid integrity value")
+}
+
+// TestVerifyIntegrity tests hash verification with the strongest algorithm
+func TestVerifyIntegrity(t *testing.T) {
+	content := []byte("alert('hello')")
+	sha256, err := ComputeIntegrity("sha256", content)
+	require.NoError(t, err)
+	sha384, err := ComputeIntegrity("sha384", content)
+	require.NoError(t, err)
+
+	assert.NoError(t, VerifyIntegrity(sha384, content))
+	assert.NoError(t, VerifyIntegrity(sha256+" "+sha384, content))
+	assert.ErrorContains(t, VerifyIntegrity(sha384, []byte("tampered")), "integrity mismatch")
+
+	// Only the strongest algorithm counts, as in browsers
+	wrong384, err := ComputeIntegrity("sha384", []byte("other"))
+	require.NoError(t, err)
+	assert.Error(t, VerifyIntegrity(sha256+" "+wrong384, content))
+}
+
+// TestFetchers tests the fixture fetcher against integrity values
+func TestFetchers(t *testing.T) {
+	content := []byte("body { color: red }")
+	integrity, err := ComputeIntegrity("sha384", content)
+	require.NoError(t, err)
+
+	t.Run("fixture fetcher", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "cdn.example.com", "1.0", "lib.css")
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, content, 0o644))
+
+		fetched, err := FixtureFetcher(dir)("https://cdn.example.com/1.0/lib.css")
+		require.NoError(t, err)
+		assert.NoError(t, VerifyIntegrity(integrity, fetched))
+
+		_, err = FixtureFetcher(dir)("https://cdn.example.com/2.0/lib.css")
+		assert.ErrorContains(t, err, "no fixture")
+	})
+
+	t.Run("fixture fetcher stays in its directory", func(t *testing.T) {
+		root := t.TempDir()
+		dir := filepath.Join(root, "cdn")
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "cdn.example.com"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "secret.css"), content, 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "cdn.example.com", "lib.css"), content, 0o644))
+
+		for _, rawURL := range []string{
+			"https://cdn.example.com/../../secret.css",
+			"https://cdn.example.com/%2e%2e/%2e%2e/secret.css",
+			"https://cdn.example.com/1.0/../lib.css",
+			"https://../secret.css",
+			"file:///secret.css",
+		} {
+			_, err := FixtureFetcher(dir)(rawURL)
+			assert.ErrorContains(t, err, "refusing fixture path", rawURL)
+		}
+	})
+}
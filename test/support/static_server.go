@@ -0,0 +1,68 @@
+package support
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// StaticServer serves a built site directory the way a static host does,
+// including the site's 404.html for missing paths
+type StaticServer struct {
+	server *httptest.Server
+	dir    string
+}
+
+// NewStaticServer starts serving dir on a random local port
+func NewStaticServer(dir string) *StaticServer {
+	s := &StaticServer{dir: dir}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// URL returns the server's base URL without a trailing slash
+func (s *StaticServer) URL() string {
+	return s.server.URL
+}
+
+// Dir returns the directory being served
+func (s *StaticServer) Dir() string {
+	return s.dir
+}
+
+// Close stops the server
+func (s *StaticServer) Close() {
+	s.server.Close()
+}
+
+// serve resolves pretty URLs to index.html and falls back to 404.html
+func (s *StaticServer) serve(w http.ResponseWriter, r *http.Request) {
+	name := filepath.Join(s.dir, filepath.FromSlash(path.Clean("/"+r.URL.Path)))
+
+	info, err := os.Stat(name)
+	if err == nil && info.IsDir() {
+		if !strings.HasSuffix(r.URL.Path, "/") {
+			http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+			return
+		}
+		name = filepath.Join(name, "index.html")
+		_, err = os.Stat(name)
+	}
+
+	if err != nil {
+		notFound, readErr := os.ReadFile(filepath.Join(s.dir, "404.html"))
+		if readErr != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write(notFound)
+		return
+	}
+
+	http.ServeFile(w, r, name)
+}
@@ -0,0 +1,49 @@
+package support
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStaticServer tests pretty URLs, redirects and the 404 fallback
+func TestStaticServer(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "blog"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("home"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "blog", "index.html"), []byte("blog"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "404.html"), []byte("not found"), 0o644))
+
+	server := NewStaticServer(dir)
+	defer server.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(server.URL() + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := get("/")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "home", body)
+
+	// Redirected from /blog to /blog/
+	status, body = get("/blog")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "blog", body)
+
+	status, body = get("/missing/")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "not found", body)
+
+	status, _ = get("/../../etc/passwd")
+	assert.Equal(t, http.StatusNotFound, status)
+}
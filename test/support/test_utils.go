@@ -3,9 +3,11 @@ package support
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"testing"
 
+	"github.com/playwright-community/playwright-go"
 	"github.com/pwarnock/go-playwright-testkit/pkg/browser"
 	pkgcontext "github.com/pwarnock/go-playwright-testkit/pkg/context"
 	"github.com/pwarnock/go-playwright-testkit/pkg/logger"
@@ -15,7 +17,17 @@ import (
 // TestContext wraps the library's TestContext with Hugo-specific functionality
 type TestContext struct {
 	*pkgcontext.TestContext
-	HugoServer *HugoServer
+	HugoServer   *HugoServer
+	ScenarioName string
+	ScenarioID   string // godog pickle ID, unique per Scenario Outline row
+	Recording    RecordingOptions
+	Monitor      *PageMonitor
+	Network      *NetworkRouter
+	Build        *BuiltSite // static build being inspected, see UseBuild
+
+	recorder     *Recorder
+	page         playwright.Page
+	staticServer *StaticServer
 }
 
 // NewTestContext creates a new test context for Hugo site testing
@@ -50,8 +62,46 @@ func (tc *TestContext) Setup() error {
 	return nil
 }
 
+// ServeBuild serves a static site build and points BaseURL at it
+func (tc *TestContext) ServeBuild(server *StaticServer) {
+	tc.stopStaticServer()
+	tc.staticServer = server
+	tc.BaseURL = server.URL()
+	tc.Logf("Serving static build from %s at %s", server.Dir(), server.URL())
+}
+
+// UseBuild records a static build for file-level checks, replacing and
+// removing any previous one
+func (tc *TestContext) UseBuild(site *BuiltSite) {
+	tc.removeBuild()
+	tc.Build = site
+	tc.Logf("Inspecting %s build in %s", site.Environment, site.Dir)
+}
+
+// removeBuild deletes the build recorded by UseBuild
+func (tc *TestContext) removeBuild() {
+	if tc.Build == nil {
+		return
+	}
+	os.RemoveAll(tc.Build.Dir)
+	tc.Build = nil
+}
+
+// stopStaticServer shuts down any served build and removes its files
+func (tc *TestContext) stopStaticServer() {
+	if tc.staticServer == nil {
+		return
+	}
+	tc.staticServer.Close()
+	os.RemoveAll(tc.staticServer.Dir())
+	tc.staticServer = nil
+}
+
 // Teardown cleans up test environment
 func (tc *TestContext) Teardown() {
+	tc.stopStaticServer()
+	tc.removeBuild()
+
 	// Call parent Teardown which handles browser and server cleanup
 	tc.TestContext.Teardown()
 
@@ -81,6 +131,120 @@ func (tc *TestContext) SetupBrowser() error {
 	return nil
 }
 
+// Page returns the page steps should drive, preferring the recorded page when recording
+func (tc *TestContext) Page() playwright.Page {
+	if tc.page != nil {
+		return tc.page
+	}
+	if tc.Browser != nil {
+		return tc.Browser.GetPage()
+	}
+	return nil
+}
+
+// StartRecording begins tracing/video for the scenario if recording is enabled
+func (tc *TestContext) StartRecording() error {
+	if !tc.Recording.Enabled() || tc.recorder != nil || tc.Browser == nil {
+		return nil
+	}
+
+	tc.recorder = NewRecorder(tc.Recording, tc.ScenarioName, tc.ScenarioID)
+	page, err := tc.recorder.Attach(tc.Browser.GetPage())
+	tc.page = page
+	if err != nil {
+		return err
+	}
+
+	tc.Logf("Recording scenario (trace=%t, video=%t, retain=%s)",
+		tc.Recording.Trace, tc.Recording.Video, tc.Recording.Retention)
+	return nil
+}
+
+// StopRecording finishes recording and reports any artifacts that were kept
+func (tc *TestContext) StopRecording(failed bool) error {
+	if tc.recorder == nil {
+		return nil
+	}
+
+	kept, err := tc.recorder.Stop(failed)
+	for _, path := range kept {
+		tc.Logf("Saved recording: %s", path)
+	}
+
+	tc.recorder = nil
+	tc.page = nil
+	return err
+}
+
+// StartMonitoring subscribes to console, pageerror and network events on the active page
+func (tc *TestContext) StartMonitoring() error {
+	if tc.Monitor != nil {
+		return nil
+	}
+	page := tc.Page()
+	if page == nil {
+		return fmt.Errorf("no page to monitor")
+	}
+
+	allowlist, err := LoadAllowlist(DefaultAllowlistPath)
+	if err != nil {
+		return err
+	}
+
+	tc.Monitor = NewPageMonitor(allowlist)
+	tc.Monitor.Attach(page)
+	return nil
+}
+
+// StartNetworkRouting routes the page's requests through a NetworkRouter
+func (tc *TestContext) StartNetworkRouting() error {
+	if tc.Network != nil {
+		return nil
+	}
+	page := tc.Page()
+	if page == nil {
+		return fmt.Errorf("no page to route")
+	}
+
+	router := NetworkRouterFromEnv(tc.BaseURL)
+	if err := router.Install(page.Context()); err != nil {
+		return err
+	}
+
+	tc.Network = router
+	return nil
+}
+
+// PreparePage creates the browser if needed and starts recording, network
+// routing and page monitoring, so steps that must act before the first navigation (routing,
+// storage) have a page to work with
+func (tc *TestContext) PreparePage() error {
+	if tc.Browser != nil {
+		return nil
+	}
+
+	if err := tc.SetupBrowser(); err != nil {
+		return err
+	}
+
+	// Start tracing/video before the first navigation so it is captured
+	if err := tc.StartRecording(); err != nil {
+		tc.Logf("Warning: could not start recording: %v", err)
+	}
+
+	// Stub third-party hosts and record requests before anything loads
+	if err := tc.StartNetworkRouting(); err != nil {
+		return err
+	}
+
+	// Capture JavaScript errors and failed requests for every page visited
+	if err := tc.StartMonitoring(); err != nil {
+		return fmt.Errorf("could not monitor page: %w", err)
+	}
+
+	return nil
+}
+
 // CheckPageExists checks if a page responds correctly
 func (tc *TestContext) CheckPageExists(pageName string) error {
 	url := tc.GetPageURL(pageName)
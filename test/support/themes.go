@@ -0,0 +1,94 @@
+package support
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/playwright-community/playwright-go"
+	"gopkg.in/yaml.v3"
+)
+
+// SiteDir is the Hugo site source, relative to the test directory
+const SiteDir = "../packages/site"
+
+// ThemesDataPath is the theme catalogue read by the site's theme selector
+const ThemesDataPath = SiteDir + "/data/themes.yaml"
+
+// themeStorageKey is the localStorage key the site's setTheme() persists to
+const themeStorageKey = "theme"
+
+// Theme is a DaisyUI theme entry from data/themes.yaml
+type Theme struct {
+	ID          string `yaml:"id"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Accessible  bool   `yaml:"accessible"`
+	Contrast    string `yaml:"contrast"`
+}
+
+// themesFile mirrors the top level of data/themes.yaml
+type themesFile struct {
+	Themes []Theme `yaml:"themes"`
+}
+
+// LoadThemes reads the theme catalogue
+func LoadThemes(path string) ([]Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read themes %s: %w", path, err)
+	}
+
+	var file themesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse themes %s: %w", path, err)
+	}
+
+	return file.Themes, nil
+}
+
+// AccessibleThemes returns the themes marked accessible: true
+func AccessibleThemes(themes []Theme) []Theme {
+	var accessible []Theme
+	for _, theme := range themes {
+		if theme.Accessible {
+			accessible = append(accessible, theme)
+		}
+	}
+	return accessible
+}
+
+// FindTheme looks up a theme by ID
+func FindTheme(themes []Theme, id string) (Theme, bool) {
+	for _, theme := range themes {
+		if theme.ID == id {
+			return theme, true
+		}
+	}
+	return Theme{}, false
+}
+
+// ApplyTheme switches the page to a theme the way the site's theme selector does:
+// data-theme on <html> plus the persisted localStorage value. localStorage is
+// written once, so a theme that survives later navigations was restored by
+// the site itself.
+func ApplyTheme(page playwright.Page, id string) error {
+	_, err := page.Evaluate(`([key, id]) => {
+		localStorage.setItem(key, id);
+		document.documentElement.setAttribute('data-theme', id);
+	}`, []interface{}{themeStorageKey, id})
+	if err != nil {
+		return fmt.Errorf("failed to apply theme %s: %w", id, err)
+	}
+
+	// Let DaisyUI's CSS variables repaint before anything measures colors
+	return WaitForLayoutSettled(page)
+}
+
+// CurrentTheme returns the page's active data-theme
+func CurrentTheme(page playwright.Page) (string, error) {
+	theme, err := page.Locator("html").GetAttribute("data-theme")
+	if err != nil {
+		return "", fmt.Errorf("could not read data-theme: %w", err)
+	}
+	return theme, nil
+}
@@ -0,0 +1,54 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadThemes_SiteData tests parsing the site's theme catalogue
+func TestLoadThemes_SiteData(t *testing.T) {
+	themes, err := LoadThemes(filepath.Join("..", ThemesDataPath))
+	require.NoError(t, err)
+	require.NotEmpty(t, themes)
+
+	light, ok := FindTheme(themes, "light")
+	require.True(t, ok)
+	assert.True(t, light.Accessible)
+	assert.Equal(t, "high", light.Contrast)
+
+	for _, theme := range AccessibleThemes(themes) {
+		assert.True(t, theme.Accessible, theme.ID)
+	}
+}
+
+// TestLoadThemes_Errors tests missing and malformed files
+func TestLoadThemes_Errors(t *testing.T) {
+	_, err := LoadThemes(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read themes")
+
+	path := filepath.Join(t.TempDir(), "themes.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("themes: [unclosed"), 0o644))
+	_, err = LoadThemes(path)
+	assert.ErrorContains(t, err, "failed to parse themes")
+}
+
+// TestAccessibleThemes tests filtering on the accessible flag
+func TestAccessibleThemes(t *testing.T) {
+	themes := []Theme{
+		{ID: "light", Accessible: true},
+		{ID: "acid", Accessible: false},
+		{ID: "dark", Accessible: true},
+	}
+
+	accessible := AccessibleThemes(themes)
+	require.Len(t, accessible, 2)
+	assert.Equal(t, "light", accessible[0].ID)
+	assert.Equal(t, "dark", accessible[1].ID)
+
+	_, ok := FindTheme(themes, "cyberpunk")
+	assert.False(t, ok)
+}
@@ -0,0 +1,344 @@
+package support
+
+import (
+	"fmt"
+	"html"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Environments with special rules for leaked URLs
+const (
+	// DevelopmentEnvironment is the only environment allowed to reference local servers
+	DevelopmentEnvironment = "development"
+	// ProductionEnvironment may be linked to from other environments, but not canonical
+	ProductionEnvironment = "production"
+)
+
+// leakScanExtensions are the generated files scanned for leaked URLs
+var leakScanExtensions = map[string]bool{".html": true, ".json": true, ".xml": true}
+
+var (
+	// jsonURLPattern matches JSON string values that are whole URLs, which may escape slashes
+	jsonURLPattern = regexp.MustCompile(`"(https?:(?:\\?/){2}[^"\s]*)"`)
+	// xmlURLPattern matches URL elements and attributes in sitemaps and feeds;
+	// markup escaped inside <description> is not matched
+	xmlURLPattern = regexp.MustCompile(`<(?:loc|link|guid|id|url)>\s*([^<\s]+)\s*</|\b(?:href|src|url)="([^"]*)"`)
+	// xmlCanonicalPattern matches sitemap <loc> and feed <link> values
+	xmlCanonicalPattern = regexp.MustCompile(`<(loc|link)>\s*([^<\s]+)\s*</(?:loc|link)>`)
+)
+
+// htmlURLAttributes are the attributes whose values are URLs the page links to or loads
+var htmlURLAttributes = []string{"href", "src", "action", "poster", "data-src", "srcset"}
+
+// URLLeak is a reference in generated output to the wrong environment
+type URLLeak struct {
+	File   string // path relative to the build directory
+	Line   int
+	Rule   string
+	Detail string
+}
+
+// String formats the leak for failure messages
+func (l URLLeak) String() string {
+	return fmt.Sprintf("%s:%d [%s] %s", l.File, l.Line, l.Rule, l.Detail)
+}
+
+// ConfiguredEnvironments lists the environments with a config/<env>/hugo.toml
+func ConfiguredEnvironments() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(RootDir, "config"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	var environments []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(EnvironmentConfigPath(entry.Name())); err == nil {
+			environments = append(environments, entry.Name())
+		}
+	}
+	sort.Strings(environments)
+	return environments, nil
+}
+
+// EnvironmentBaseURLs returns the baseURL of every configured environment
+func EnvironmentBaseURLs() (map[string]string, error) {
+	environments, err := ConfiguredEnvironments()
+	if err != nil {
+		return nil, err
+	}
+
+	baseURLs := make(map[string]string, len(environments))
+	for _, env := range environments {
+		baseURL, err := EnvironmentBaseURL(env)
+		if err != nil {
+			return nil, err
+		}
+		baseURLs[env] = baseURL
+	}
+	return baseURLs, nil
+}
+
+// LeakDetector finds references to other environments in one environment's output
+type LeakDetector struct {
+	environment string
+	baseURL     *url.URL
+	// foreignHosts must never appear; canonicalHosts must not appear in canonical URLs
+	foreignHosts   map[string]string
+	canonicalHosts map[string]string
+}
+
+// NewLeakDetector creates a detector for environment given every environment's baseURL.
+// Hosts shared with the environment's own baseURL are never reported.
+func NewLeakDetector(environment string, baseURLs map[string]string) (*LeakDetector, error) {
+	own, ok := baseURLs[environment]
+	if !ok {
+		return nil, fmt.Errorf("no baseURL for environment %q", environment)
+	}
+	ownURL, err := url.Parse(own)
+	if err != nil {
+		return nil, fmt.Errorf("invalid baseURL %q for %s: %w", own, environment, err)
+	}
+
+	d := &LeakDetector{
+		environment:    environment,
+		baseURL:        ownURL,
+		foreignHosts:   make(map[string]string),
+		canonicalHosts: make(map[string]string),
+	}
+
+	for env, other := range baseURLs {
+		host := hostOf(other)
+		if env == environment || host == "" || host == strings.ToLower(ownURL.Hostname()) || isLocalHost(host) {
+			continue
+		}
+		if env == ProductionEnvironment {
+			// Linking to the live site is fine; claiming to be it is not
+			d.canonicalHosts[host] = env
+		} else {
+			// Pre-production hosts must never reach another environment's output
+			d.foreignHosts[host] = env
+		}
+	}
+
+	return d, nil
+}
+
+// isLocalHost reports whether host is a local dev server
+func isLocalHost(host string) bool {
+	switch strings.ToLower(host) {
+	case "localhost", "127.0.0.1", "0.0.0.0":
+		return true
+	}
+	return false
+}
+
+// CheckFile returns the leaks in one generated file's content
+func (d *LeakDetector) CheckFile(rel, content string) []URLLeak {
+	var leaks []URLLeak
+	add := func(offset int, rule, detail string) {
+		leaks = append(leaks, URLLeak{
+			File:   rel,
+			Line:   strings.Count(content[:offset], "\n") + 1,
+			Rule:   rule,
+			Detail: detail,
+		})
+	}
+
+	// Only URLs the output links to or loads count; text such as code samples
+	// showing http://localhost:3000 is not a leak
+	for _, ref := range urlReferences(rel, content) {
+		u, err := url.Parse(ref.url)
+		if err != nil {
+			continue
+		}
+		host := strings.ToLower(u.Hostname())
+
+		switch {
+		case !isLocalHost(host):
+		case d.environment != DevelopmentEnvironment:
+			add(ref.offset, "localhost", ref.url+" in "+d.environment+" output")
+		case isLocalHost(d.baseURL.Hostname()) && u.Port() != d.baseURL.Port():
+			add(ref.offset, "worktree-port", fmt.Sprintf("%s does not match the development baseURL %s", ref.url, d.baseURL))
+		}
+
+		if d.environment != DevelopmentEnvironment && strings.HasSuffix(u.Path, "livereload.js") {
+			add(ref.offset, "livereload", "livereload.js in "+d.environment+" output")
+		}
+
+		for foreign, env := range d.foreignHosts {
+			if hostMatches(host, foreign) {
+				add(ref.offset, "foreign-host", fmt.Sprintf("%s host %s in %s output", env, foreign, d.environment))
+			}
+		}
+	}
+
+	for _, ref := range canonicalReferences(rel, content) {
+		if env, ok := d.canonicalHosts[hostOf(ref.url)]; ok {
+			add(ref.offset, "canonical", fmt.Sprintf("%s points at the %s site: %s", ref.kind, env, ref.url))
+		}
+	}
+
+	return leaks
+}
+
+// urlRef is a URL found in generated output
+type urlRef struct {
+	url    string
+	offset int
+}
+
+// urlReferences finds the URLs a file links to or loads: URL attributes and
+// JSON-LD in HTML, URL elements and attributes in XML, and string values that
+// are whole URLs in JSON. Text content, including <pre> and <code> blocks, is
+// not inspected.
+func urlReferences(rel, content string) []urlRef {
+	var refs []urlRef
+
+	switch filepath.Ext(rel) {
+	case ".html":
+		lineOffsets := lineStartOffsets(content)
+		for _, tag := range ParseTags(content) {
+			offset := lineOffsets[tag.Line-1]
+			for _, name := range htmlURLAttributes {
+				value := strings.TrimSpace(tag.Attr(name))
+				if value == "" {
+					continue
+				}
+				if name == "srcset" {
+					for _, candidate := range strings.Split(value, ",") {
+						if fields := strings.Fields(candidate); len(fields) > 0 {
+							refs = append(refs, urlRef{fields[0], offset})
+						}
+					}
+					continue
+				}
+				refs = append(refs, urlRef{value, offset})
+			}
+			if tag.Name == "meta" && strings.Contains(tag.Attr("content"), "://") {
+				refs = append(refs, urlRef{strings.TrimSpace(tag.Attr("content")), offset})
+			}
+			if tag.Name == "script" && strings.EqualFold(tag.Attr("type"), "application/ld+json") {
+				for _, m := range jsonURLPattern.FindAllStringSubmatch(tag.Text, -1) {
+					refs = append(refs, urlRef{strings.ReplaceAll(m[1], `\/`, "/"), offset})
+				}
+			}
+		}
+	case ".xml":
+		for _, m := range xmlURLPattern.FindAllStringSubmatchIndex(content, -1) {
+			value := ""
+			if m[2] >= 0 {
+				value = content[m[2]:m[3]]
+			} else {
+				value = content[m[4]:m[5]]
+			}
+			refs = append(refs, urlRef{html.UnescapeString(value), m[0]})
+		}
+	case ".json":
+		for _, m := range jsonURLPattern.FindAllStringSubmatchIndex(content, -1) {
+			refs = append(refs, urlRef{strings.ReplaceAll(content[m[2]:m[3]], `\/`, "/"), m[0]})
+		}
+	}
+
+	return refs
+}
+
+// canonicalRef is a URL claiming to be a page's own address
+type canonicalRef struct {
+	kind   string
+	url    string
+	offset int
+}
+
+// canonicalReferences finds canonical and og:url tags in HTML and <loc>/<link>
+// entries in sitemaps and feeds
+func canonicalReferences(rel, content string) []canonicalRef {
+	var refs []canonicalRef
+
+	switch filepath.Ext(rel) {
+	case ".html":
+		lineOffsets := lineStartOffsets(content)
+		for _, tag := range ParseTags(content) {
+			offset := lineOffsets[tag.Line-1]
+			switch {
+			case tag.Name == "link" && hasAnyRel(tag.Attr("rel"), map[string]bool{"canonical": true}):
+				refs = append(refs, canonicalRef{"canonical link", tag.Attr("href"), offset})
+			case tag.Name == "meta" && strings.EqualFold(tag.Attr("property"), "og:url"):
+				refs = append(refs, canonicalRef{"og:url", tag.Attr("content"), offset})
+			}
+		}
+	case ".xml":
+		for _, m := range xmlCanonicalPattern.FindAllStringSubmatchIndex(content, -1) {
+			refs = append(refs, canonicalRef{"<" + content[m[2]:m[3]] + ">", content[m[4]:m[5]], m[0]})
+		}
+	}
+
+	return refs
+}
+
+// lineStartOffsets returns the byte offset at which each line starts
+func lineStartOffsets(content string) []int {
+	offsets := []int{0}
+	for i, c := range content {
+		if c == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+// ScanBuildForLeaks checks every HTML, JSON and XML file in a build
+func (d *LeakDetector) ScanBuildForLeaks(dir string) ([]URLLeak, error) {
+	var leaks []URLLeak
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !leakScanExtensions[filepath.Ext(path)] {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		leaks = append(leaks, d.CheckFile(filepath.ToSlash(rel), string(data))...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+
+	sort.SliceStable(leaks, func(i, j int) bool {
+		if leaks[i].File != leaks[j].File {
+			return leaks[i].File < leaks[j].File
+		}
+		return leaks[i].Line < leaks[j].Line
+	})
+	return leaks, nil
+}
+
+// SummarizeLeaks renders leaks as an indented list, capped at limit entries
+func SummarizeLeaks(leaks []URLLeak, limit int) string {
+	var b strings.Builder
+	for i, leak := range leaks {
+		if limit > 0 && i == limit {
+			fmt.Fprintf(&b, "  ... and %d more\n", len(leaks)-limit)
+			break
+		}
+		fmt.Fprintf(&b, "  - %s\n", leak)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,147 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testBaseURLs = map[string]string{
+	"development": "http://localhost:1313",
+	"staging":     "https://staging.pwarnock.github.io",
+	"production":  "https://peterwarnock.com",
+}
+
+// rules returns the rule names of leaks, in order
+func rules(leaks []URLLeak) []string {
+	var names []string
+	for _, leak := range leaks {
+		names = append(names, leak.Rule)
+	}
+	return names
+}
+
+// TestLeakDetector_Production tests production output rules
+func TestLeakDetector_Production(t *testing.T) {
+	d, err := NewLeakDetector("production", testBaseURLs)
+	require.NoError(t, err)
+
+	clean := `<link rel="canonical" href="https://peterwarnock.com/blog/">
+<a href="https://peterwarnock.com/about/">About</a>`
+	assert.Empty(t, d.CheckFile("blog/index.html", clean))
+
+	leaky := `<link rel="canonical" href="http://localhost:1313/blog/">
+<script src="/livereload.js?port=1313"></script>
+<a href="https://staging.pwarnock.github.io/">Staging</a>`
+	leaks := d.CheckFile("blog/index.html", leaky)
+	assert.Equal(t, []string{"localhost", "livereload", "foreign-host"}, rules(leaks))
+	assert.Equal(t, 3, leaks[2].Line)
+
+	// JSON output escapes slashes
+	assert.Equal(t, []string{"localhost"}, rules(d.CheckFile("index.json", `{"permalink":"http:\/\/localhost:1313\/blog\/"}`)))
+}
+
+// TestLeakDetector_Staging tests production hosts are only leaks in canonical URLs
+func TestLeakDetector_Staging(t *testing.T) {
+	d, err := NewLeakDetector("staging", testBaseURLs)
+	require.NoError(t, err)
+
+	assert.Empty(t, d.CheckFile("index.html", `<a href="https://peterwarnock.com/">Live site</a>`))
+
+	html := `<link rel="canonical" href="https://peterwarnock.com/">
+<meta property="og:url" content="https://peterwarnock.com/">`
+	assert.Equal(t, []string{"canonical", "canonical"}, rules(d.CheckFile("index.html", html)))
+
+	sitemap := "<urlset>\n<url><loc>https://peterwarnock.com/blog/</loc></url>\n</urlset>"
+	leaks := d.CheckFile("sitemap.xml", sitemap)
+	require.Len(t, leaks, 1)
+	assert.Equal(t, 2, leaks[0].Line)
+	assert.Contains(t, leaks[0].Detail, "<loc> points at the production site")
+}
+
+// TestLeakDetector_Development tests worktree ports and livereload in development
+func TestLeakDetector_Development(t *testing.T) {
+	d, err := NewLeakDetector("development", testBaseURLs)
+	require.NoError(t, err)
+
+	assert.Empty(t, d.CheckFile("index.html", `<a href="http://localhost:1313/blog/">`+
+		`<script src="/livereload.js"></script>`))
+
+	leaks := d.CheckFile("index.html", `<a href="http://localhost:1314/blog/">`)
+	assert.Equal(t, []string{"worktree-port"}, rules(leaks))
+
+	assert.Equal(t, []string{"canonical"}, rules(d.CheckFile("index.html", `<link rel=canonical href=https://peterwarnock.com/>`)))
+
+	_, err = NewLeakDetector("qa", testBaseURLs)
+	assert.ErrorContains(t, err, `no baseURL for environment "qa"`)
+}
+
+// TestLeakDetector_CodeSamples tests URLs in code blocks and text are not leaks,
+// as on the playwright and godog tool pages
+func TestLeakDetector_CodeSamples(t *testing.T) {
+	page := `<pre><code class="language-bash">npx playwright test --base-url http://localhost:3000
+</code></pre>
+<p>Point godog at <code>http://localhost:8080</code> or staging.pwarnock.github.io.</p>
+<script type="application/ld+json">{"url":"https://peterwarnock.com/tools/godog/"}</script>`
+	feed := `<item><title>Godog</title>
+<description>&lt;pre&gt;&lt;code&gt;curl http://localhost:8080/&lt;/code&gt;&lt;/pre&gt;</description></item>`
+	index := `{"permalink":"https:\/\/peterwarnock.com\/tools\/godog\/","content":"run against http://localhost:8080 first"}`
+
+	for _, env := range []string{"development", "staging", "production"} {
+		d, err := NewLeakDetector(env, testBaseURLs)
+		require.NoError(t, err)
+
+		assert.Empty(t, rules(d.CheckFile("tools/godog/index.html", page)), env)
+		assert.Empty(t, rules(d.CheckFile("tools/godog/index.xml", feed)), env)
+		assert.NotContains(t, rules(d.CheckFile("index.json", index)), "localhost", env)
+	}
+
+	// The same URLs in attributes and JSON-LD still leak
+	d, err := NewLeakDetector("production", testBaseURLs)
+	require.NoError(t, err)
+	linked := `<a href="http://localhost:3000/">demo</a>
+<script type="application/ld+json">{"url":"https://staging.pwarnock.github.io/"}</script>`
+	assert.Equal(t, []string{"localhost", "foreign-host"}, rules(d.CheckFile("index.html", linked)))
+}
+
+// TestScanBuildForLeaks tests only HTML, JSON and XML files are scanned
+func TestScanBuildForLeaks(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"index.html":    `<a href="http://localhost:1313/">`,
+		"index.xml":     `<link>http://localhost:1313/</link>`,
+		"js/app.js":     `fetch("http://localhost:1313/api")`,
+		"blog/a/x.json": `{}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	d, err := NewLeakDetector("production", testBaseURLs)
+	require.NoError(t, err)
+
+	leaks, err := d.ScanBuildForLeaks(dir)
+	require.NoError(t, err)
+	require.Len(t, leaks, 2)
+	assert.Equal(t, "index.html", leaks[0].File)
+	assert.Equal(t, "index.xml", leaks[1].File)
+	assert.Contains(t, SummarizeLeaks(leaks, 1), "... and 1 more")
+}
+
+// TestEnvironmentBaseURLs tests environments are discovered from config/
+func TestEnvironmentBaseURLs(t *testing.T) {
+	t.Chdir("..")
+
+	environments, err := ConfiguredEnvironments()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"development", "production", "staging"}, environments)
+
+	baseURLs, err := EnvironmentBaseURLs()
+	require.NoError(t, err)
+	assert.Equal(t, testBaseURLs, baseURLs)
+}
@@ -0,0 +1,82 @@
+package support
+
+import (
+	"fmt"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// navigationTimeout bounds click-triggered navigations (ms)
+const navigationTimeout = 15000
+
+// layoutSettleFrames is how many consecutive unchanged animation frames count as settled
+const layoutSettleFrames = 3
+
+// layoutSettleTimeout bounds how long to wait for layout to settle (ms)
+const layoutSettleTimeout = 3000
+
+// ClickAndWaitForNavigation clicks the locator and waits until the resulting navigation
+// commits and the new document reaches the load state. The returned response is nil for
+// same-document navigations such as hash changes.
+func ClickAndWaitForNavigation(page playwright.Page, target playwright.Locator) (playwright.Response, error) {
+	resp, err := page.ExpectNavigation(func() error {
+		return target.Click()
+	}, playwright.PageExpectNavigationOptions{
+		WaitUntil: playwright.WaitUntilStateLoad,
+		Timeout:   playwright.Float(navigationTimeout),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("navigation did not complete after click: %w", err)
+	}
+
+	return resp, nil
+}
+
+// PressAndWaitForNavigation presses a key on the focused element, e.g. Enter
+// on a link, and waits for the resulting navigation to load
+func PressAndWaitForNavigation(page playwright.Page, key string) (playwright.Response, error) {
+	resp, err := page.ExpectNavigation(func() error {
+		return page.Keyboard().Press(key)
+	}, playwright.PageExpectNavigationOptions{
+		WaitUntil: playwright.WaitUntilStateLoad,
+		Timeout:   playwright.Float(navigationTimeout),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("navigation did not complete after pressing %s: %w", key, err)
+	}
+
+	return resp, nil
+}
+
+// WaitForLayoutSettled waits until the document's size and scroll extents stop changing
+// across consecutive animation frames, e.g. after a viewport resize triggers a
+// responsive layout change
+func WaitForLayoutSettled(page playwright.Page) error {
+	_, err := page.Evaluate(`([stableFrames, timeout]) => new Promise((resolve, reject) => {
+		const deadline = performance.now() + timeout;
+		const measure = () => {
+			const el = document.documentElement;
+			return [window.innerWidth, window.innerHeight, el.scrollWidth, el.scrollHeight, el.clientWidth].join('x');
+		};
+		let last = measure();
+		let stable = 0;
+		const tick = () => {
+			const current = measure();
+			stable = current === last ? stable + 1 : 0;
+			last = current;
+			if (stable >= stableFrames) {
+				resolve(true);
+			} else if (performance.now() > deadline) {
+				reject(new Error('layout did not settle within ' + timeout + 'ms'));
+			} else {
+				requestAnimationFrame(tick);
+			}
+		};
+		requestAnimationFrame(tick);
+	})`, []interface{}{layoutSettleFrames, layoutSettleTimeout})
+	if err != nil {
+		return fmt.Errorf("could not wait for layout to settle: %w", err)
+	}
+
+	return nil
+}